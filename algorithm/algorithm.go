@@ -9,7 +9,40 @@ type Algorithm interface {
 	NodeSelected(node *graph.Node)
 	UndoSelect()
 	Start(*graph.Graph) error;
-	Update() bool;
+	// advance the algorithm by one step, false once it is finished or failed
+	Update() (bool, error);
 	GetName() string;
+	// outcome of the last run, nil until it finished without an error
+	Result() *Result
+}
+
+// An algorithm with variants the user can cycle through, like the heuristic of A*
+type Configurable interface {
+	NextOption()
+	OptionName() string
+}
+
+// An algorithm that fills tables about every pair of nodes, shown next to the graph
+type Matrix interface {
+	// how many tables there are to switch between
+	MatrixCount() int
+	MatrixTitle(table int) string
+	// labels of the rows and columns, rows are where paths start. None means
+	// there is nothing to show yet
+	MatrixLabels() []string
+	MatrixCell(table, row, col int) string
+}
+
+// An algorithm that can turn its outcome into a new graph, like the condensation
+// of strongly connected components
+type Builder interface {
+	Build() (graph.Graph, error)
+}
+
+// An algorithm that builds a tour through nodes that do not have to be connected by
+// edges, like a travelling salesman tour, drawn over the graph
+type Tour interface {
+	// the nodes in the order of the tour, closed when it goes back to the first one
+	Tour() (nodes []*graph.Node, closed bool)
 }
 
@@ -0,0 +1,81 @@
+package algorithm
+
+import (
+	"graphographic/graph"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Build a graph from "tail head cost" triples separated by commas, nodes are
+// named by their content and created on first use. "a - b cost" is an undirected
+// pair of edges like the UNDIRECTED connect mode makes
+func buildGraph(t *testing.T, edges string) (*graph.Graph, map[string]*graph.Node) {
+	t.Helper()
+	g := graph.New()
+	nodes := make(map[string]*graph.Node)
+	node := func(name string) *graph.Node {
+		if n, ok := nodes[name]; ok {
+			return n
+		}
+		n := graph.NewNode()
+		n.Content = name
+		nodes[name] = g.AddNode(n)
+		return nodes[name]
+	}
+	for _, spec := range strings.Split(edges, ",") {
+		fields := strings.Fields(spec)
+		switch len(fields) {
+		case 0:
+			continue
+		case 1:
+			node(fields[0])
+			continue
+		}
+		paired := len(fields) > 2 && fields[1] == "-"
+		if paired {
+			fields = append(fields[:1], fields[2:]...)
+		}
+		tail, head := node(fields[0]), node(fields[1])
+		edge := g.AddEdge(tail, head)
+		if len(fields) > 2 {
+			cost, err := strconv.Atoi(fields[2])
+			if err != nil {
+				t.Fatalf("bad cost in %q", spec)
+			}
+			edge.Cost = int32(cost)
+		}
+		if paired && tail == head {
+			g.PairEdges(edge, edge)
+		} else if paired {
+			reverse := g.AddEdge(head, tail)
+			reverse.Cost = edge.Cost
+			g.PairEdges(edge, reverse)
+		}
+	}
+	return &g, nodes
+}
+
+// Start the algorithm with the given nodes selected and update it until it finishes
+func runAlgorithm(t *testing.T, algo Algorithm, g *graph.Graph, selected ...*graph.Node) error {
+	t.Helper()
+	algo.Init()
+	for _, n := range selected {
+		algo.NodeSelected(n)
+	}
+	if err := algo.Start(g); err != nil {
+		return err
+	}
+	for steps := 0; ; steps++ {
+		if steps > 10*(g.NodeCount()+g.EdgeCount()+10) {
+			t.Fatalf("%s did not finish", algo.GetName())
+		}
+		running, err := algo.Update()
+		if err != nil {
+			return err
+		}
+		if !running {
+			return nil
+		}
+	}
+}
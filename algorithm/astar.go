@@ -0,0 +1,212 @@
+package algorithm
+
+import (
+	"fmt"
+	"graphographic/graph"
+	"math"
+	"slices"
+)
+
+// Estimate of the remaining cost from a node to the goal
+type Heuristic int
+
+const (
+	// without an estimate A* explores like Dijkstra
+	HeuristicZero Heuristic = iota
+	HeuristicEuclidean
+	HeuristicManhattan
+	// euclidean distance times the lowest cost per unit of length of any edge,
+	// never overestimates so the path found is always the shortest
+	HeuristicScaled
+	heuristicCount
+)
+
+func (h Heuristic) String() string {
+	switch h {
+	case HeuristicZero:
+		return "zero"
+	case HeuristicEuclidean:
+		return "euclidean"
+	case HeuristicManhattan:
+		return "manhattan"
+	case HeuristicScaled:
+		return "scaled"
+	}
+	return "unknown"
+}
+
+type aStarData struct {
+	G, H     float64
+	PrevEdge *graph.Edge
+	Closed   bool
+}
+
+type AStar struct {
+	Heuristic Heuristic
+	graph     *graph.Graph
+	start     *graph.Node
+	goal      *graph.Node
+	open      *IndexedHeap[float64, *graph.Node]
+	// cost per unit of length used by HeuristicScaled
+	scale float64
+	path  []*graph.Edge
+	done  bool
+}
+
+func (algo *AStar) Init() {
+	algo.start = nil
+	algo.goal = nil
+}
+
+func (algo *AStar) GetName() string {
+	return "A*"
+}
+
+func (algo *AStar) NextOption() {
+	algo.Heuristic = (algo.Heuristic + 1) % heuristicCount
+}
+
+func (algo *AStar) OptionName() string {
+	return algo.Heuristic.String()
+}
+
+func (algo *AStar) Start(g *graph.Graph) error {
+	if algo.start == nil || algo.goal == nil {
+		algo.start = nil
+		algo.goal = nil
+		return fmt.Errorf("Start or End node not selected")
+	}
+	algo.graph = g
+	algo.scale = math.Inf(1)
+	for e := range g.Edges() {
+		if e.Cost < 0 {
+			return fmt.Errorf("A* does not work with negative costs, use Bellman-Ford")
+		}
+		if length := distance(e.Tail, e.Head); length > 0 {
+			algo.scale = min(algo.scale, float64(e.Cost)/length)
+		}
+	}
+	if math.IsInf(algo.scale, 1) {
+		algo.scale = 0
+	}
+	for n := range g.Nodes() {
+		n.Data.Custom = &aStarData{G: math.Inf(1), H: algo.estimate(n)}
+	}
+	algo.open = NewIndexedHeap[float64, *graph.Node]()
+	startData := algo.start.Data.Custom.(*aStarData)
+	startData.G = 0
+	algo.open.Push(startData.H, algo.start)
+	algo.tag(algo.start)
+	algo.start.Data.Highlighted = false
+	algo.goal.Data.Highlighted = false
+	algo.path = nil
+	algo.done = false
+	return nil
+}
+
+func (algo *AStar) estimate(n *graph.Node) float64 {
+	dx := float64(n.Position.X - algo.goal.Position.X)
+	dy := float64(n.Position.Y - algo.goal.Position.Y)
+	switch algo.Heuristic {
+	case HeuristicEuclidean:
+		return math.Hypot(dx, dy)
+	case HeuristicManhattan:
+		return math.Abs(dx) + math.Abs(dy)
+	case HeuristicScaled:
+		return algo.scale * math.Hypot(dx, dy)
+	}
+	return 0
+}
+
+func distance(a, b *graph.Node) float64 {
+	return math.Hypot(float64(a.Position.X-b.Position.X), float64(a.Position.Y-b.Position.Y))
+}
+
+func (algo *AStar) tag(n *graph.Node) {
+	d := n.Data.Custom.(*aStarData)
+	n.Data.Tag = fmt.Sprintf("g=%.4g h=%.4g f=%.4g", d.G, d.H, d.G+d.H)
+}
+
+func (algo *AStar) Update() (bool, error) {
+	if algo.open.Len() == 0 {
+		algo.goal.Data.Tag = "Unreachable"
+		return false, fmt.Errorf("%s can not be reached from %s", algo.goal.Content, algo.start.Content)
+	}
+	_, next := algo.open.Pop()
+	nextData := next.Data.Custom.(*aStarData)
+	nextData.Closed = true
+	next.Data.Explored = true
+	if next == algo.goal {
+		algo.showPath()
+		algo.done = true
+		return false, nil
+	}
+	for edge := range next.Out() {
+		headData := edge.Head.Data.Custom.(*aStarData)
+		g := nextData.G + float64(edge.Cost)
+		if g >= headData.G {
+			continue
+		}
+		// an estimate that is not consistent can make a closed node cheaper, it is opened again
+		headData.G = g
+		headData.PrevEdge = edge
+		headData.Closed = false
+		algo.open.Push(g+headData.H, edge.Head)
+		algo.tag(edge.Head)
+	}
+	return true, nil
+}
+
+// the explored nodes stay marked, the path goes through highlighted nodes and explored edges
+func (algo *AStar) showPath() {
+	for n := algo.goal; n != nil; {
+		n.Data.Highlighted = true
+		prev := n.Data.Custom.(*aStarData).PrevEdge
+		if prev == nil {
+			break
+		}
+		prev.Data.Explored = true
+		algo.path = append(algo.path, prev)
+		n = prev.Tail
+	}
+	// collected from the goal back to the start
+	slices.Reverse(algo.path)
+}
+
+func (algo *AStar) Result() *Result {
+	if !algo.done {
+		return nil
+	}
+	result := pathResult(algo.start, algo.path)
+	closed := 0
+	for n := range algo.graph.Nodes() {
+		if n.Data.Custom.(*aStarData).Closed {
+			closed++
+		}
+	}
+	result.Metrics = append(result.Metrics, Metric{"Expanded", float64(closed)})
+	return result
+}
+
+func (algo *AStar) NodeSelected(node *graph.Node) {
+	if node == nil {
+		return
+	}
+	if algo.start == nil {
+		algo.start = node
+		algo.start.Data.Highlighted = true
+	} else if algo.goal == nil {
+		algo.goal = node
+		algo.goal.Data.Highlighted = true
+	}
+}
+
+func (algo *AStar) UndoSelect() {
+	if algo.goal != nil {
+		algo.goal.Data.Highlighted = false
+		algo.goal = nil
+	} else if algo.start != nil {
+		algo.start.Data.Highlighted = false
+		algo.start = nil
+	}
+}
@@ -0,0 +1,71 @@
+package algorithm
+
+import (
+	"fmt"
+	"graphographic/graph"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// a size x size grid of nodes 100 apart with edges both ways costing 100
+func gridGraph(size int) (*graph.Graph, [][]*graph.Node) {
+	g := graph.New()
+	nodes := make([][]*graph.Node, size)
+	for y := range size {
+		nodes[y] = make([]*graph.Node, size)
+		for x := range size {
+			n := graph.NewNode()
+			n.Content = fmt.Sprintf("%d,%d", x, y)
+			n.Position = rl.Vector2{X: float32(100 * x), Y: float32(100 * y)}
+			nodes[y][x] = g.AddNode(n)
+			if x > 0 {
+				g.AddEdge(nodes[y][x-1], nodes[y][x]).Cost = 100
+				g.AddEdge(nodes[y][x], nodes[y][x-1]).Cost = 100
+			}
+			if y > 0 {
+				g.AddEdge(nodes[y-1][x], nodes[y][x]).Cost = 100
+				g.AddEdge(nodes[y][x], nodes[y-1][x]).Cost = 100
+			}
+		}
+	}
+	return &g, nodes
+}
+
+func TestAStarHeuristics(t *testing.T) {
+	explored := make(map[Heuristic]int)
+	for h := HeuristicZero; h < heuristicCount; h++ {
+		g, nodes := gridGraph(8)
+		start, goal := nodes[0][0], nodes[7][5]
+		if err := runAlgorithm(t, &AStar{Heuristic: h}, g, start, goal); err != nil {
+			t.Fatalf("%v: %v", h, err)
+		}
+		if goal.Data.Tag != "g=1200 h=0 f=1200" {
+			t.Errorf("%v: goal is tagged %q, want a path of cost 1200", h, goal.Data.Tag)
+		}
+		for n := range g.Nodes() {
+			if n.Data.Explored {
+				explored[h]++
+			}
+		}
+	}
+	for _, h := range []Heuristic{HeuristicEuclidean, HeuristicManhattan, HeuristicScaled} {
+		if explored[h] >= explored[HeuristicZero] {
+			t.Errorf("%v explored %d nodes, no fewer than %d without a heuristic", h, explored[h], explored[HeuristicZero])
+		}
+	}
+}
+
+func TestAStarRejectsNegativeCosts(t *testing.T) {
+	g, n := buildGraph(t, "a b -1")
+	if err := runAlgorithm(t, &AStar{}, g, n["a"], n["b"]); err == nil {
+		t.Fatal("expected an error for a negative cost")
+	}
+}
+
+func TestAStarUnreachableGoal(t *testing.T) {
+	g, n := buildGraph(t, "a b 1, c")
+	if err := runAlgorithm(t, &AStar{}, g, n["a"], n["c"]); err == nil {
+		t.Fatal("expected an error for a goal that can not be reached")
+	}
+}
@@ -0,0 +1,172 @@
+package algorithm
+
+import (
+	"cmp"
+	"fmt"
+	"graphographic/graph"
+	"math"
+	"slices"
+)
+
+const unreachable = math.MaxInt64
+
+type bellmanFordData struct {
+	Dist     int64
+	PrevEdge *graph.Edge
+}
+
+// Shortest paths from one node that also work with negative costs. Every update
+// is one round of relaxing all edges, edges that improved a distance in the round
+// are highlighted
+type BellmanFord struct {
+	graph *graph.Graph
+	start *graph.Node
+	nodes []*graph.Node
+	edges []*graph.Edge
+	round int
+	done  bool
+}
+
+func (algo *BellmanFord) Init() {
+	algo.start = nil
+}
+
+func (algo *BellmanFord) GetName() string {
+	return "Bellman-Ford"
+}
+
+func (algo *BellmanFord) Start(g *graph.Graph) error {
+	if algo.start == nil {
+		return fmt.Errorf("Starting node was not selected")
+	}
+	algo.graph = g
+	algo.nodes = algo.nodes[:0]
+	algo.edges = algo.edges[:0]
+	for n := range g.Nodes() {
+		d := &bellmanFordData{Dist: unreachable}
+		if n == algo.start {
+			d.Dist = 0
+			n.Data.Tag = "0"
+		}
+		n.Data.Custom = d
+		algo.nodes = append(algo.nodes, n)
+	}
+	for e := range g.Edges() {
+		algo.edges = append(algo.edges, e)
+	}
+	algo.start.Data.Highlighted = false
+	algo.start.Data.Explored = true
+	algo.round = 0
+	algo.done = false
+	return nil
+}
+
+func (algo *BellmanFord) Update() (bool, error) {
+	for _, e := range algo.edges {
+		e.Data.Highlighted = false
+	}
+	algo.round++
+	var relaxed *graph.Edge
+	for _, e := range algo.edges {
+		if algo.relax(e) {
+			relaxed = e
+			e.Data.Highlighted = true
+		}
+	}
+	if relaxed == nil {
+		algo.showTree()
+		algo.done = true
+		return false, nil
+	}
+	// a shortest path has at most len(nodes)-1 edges, an improvement in the round
+	// after that means some path keeps getting shorter forever
+	if algo.round >= len(algo.nodes) {
+		return false, algo.showCycle(relaxed.Head)
+	}
+	return true, nil
+}
+
+func (algo *BellmanFord) relax(e *graph.Edge) bool {
+	tail := e.Tail.Data.Custom.(*bellmanFordData)
+	head := e.Head.Data.Custom.(*bellmanFordData)
+	if tail.Dist == unreachable || tail.Dist+int64(e.Cost) >= head.Dist {
+		return false
+	}
+	head.Dist = tail.Dist + int64(e.Cost)
+	head.PrevEdge = e
+	e.Head.Data.Explored = true
+	e.Head.Data.Tag = fmt.Sprintf("%d", head.Dist)
+	return true
+}
+
+// mark the edges of the shortest path tree
+func (algo *BellmanFord) showTree() {
+	for _, n := range algo.nodes {
+		d := n.Data.Custom.(*bellmanFordData)
+		if d.Dist == unreachable {
+			n.Data.Tag = "Unreachable"
+		} else if d.PrevEdge != nil {
+			d.PrevEdge.Data.Explored = true
+		}
+	}
+}
+
+// highlight the negative cycle that the predecessor chain of n leads into
+func (algo *BellmanFord) showCycle(n *graph.Node) error {
+	// n might only hang off the cycle, walking back len(nodes) steps surely ends on it
+	for range algo.nodes {
+		n = n.Data.Custom.(*bellmanFordData).PrevEdge.Tail
+	}
+	cycle := make([]*graph.Edge, 0)
+	cost := int64(0)
+	for node := n; ; {
+		e := node.Data.Custom.(*bellmanFordData).PrevEdge
+		cycle = append(cycle, e)
+		cost += int64(e.Cost)
+		if node = e.Tail; node == n {
+			break
+		}
+	}
+	// the chain was followed backwards
+	slices.Reverse(cycle)
+	return newCycleError(algo.graph, fmt.Sprintf("Negative cycle with cost %d", cost), cycle)
+}
+
+// the reachable nodes by distance and the edges of the shortest path tree
+func (algo *BellmanFord) Result() *Result {
+	if !algo.done {
+		return nil
+	}
+	result := &Result{}
+	for _, n := range algo.nodes {
+		d := n.Data.Custom.(*bellmanFordData)
+		if d.Dist == unreachable {
+			continue
+		}
+		result.Nodes = append(result.Nodes, n)
+		if d.PrevEdge != nil {
+			result.Edges = append(result.Edges, d.PrevEdge)
+		}
+	}
+	slices.SortStableFunc(result.Nodes, func(a, b *graph.Node) int {
+		return cmp.Compare(a.Data.Custom.(*bellmanFordData).Dist, b.Data.Custom.(*bellmanFordData).Dist)
+	})
+	result.Summary = fmt.Sprintf("Shortest paths from %s to %d nodes", algo.start.Content, len(result.Nodes)-1)
+	result.Metrics = []Metric{{"Reachable", float64(len(result.Nodes) - 1)}, {"Rounds", float64(algo.round)}}
+	return result
+}
+
+func (algo *BellmanFord) NodeSelected(node *graph.Node) {
+	if algo.start != nil {
+		algo.start.Data.Highlighted = false
+	}
+	if algo.start = node; algo.start != nil {
+		algo.start.Data.Highlighted = true
+	}
+}
+
+func (algo *BellmanFord) UndoSelect() {
+	if algo.start != nil {
+		algo.start.Data.Highlighted = false
+	}
+}
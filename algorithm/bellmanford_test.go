@@ -0,0 +1,50 @@
+package algorithm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBellmanFordDistances(t *testing.T) {
+	g, n := buildGraph(t, "s a 4, s b 5, b a -3, a c 2, c d -1, x")
+	if err := runAlgorithm(t, &BellmanFord{}, g, n["s"]); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"s": "0", "a": "2", "b": "5", "c": "4", "d": "3", "x": "Unreachable"}
+	for name, tag := range want {
+		if got := n[name].Data.Tag; got != tag {
+			t.Errorf("%s has tag %q, want %q", name, got, tag)
+		}
+	}
+	if g.EdgeBetween(n["s"], n["a"]).Data.Explored || !g.EdgeBetween(n["b"], n["a"]).Data.Explored {
+		t.Error("the shortest path tree should go through b to reach a")
+	}
+}
+
+func TestBellmanFordNegativeCycle(t *testing.T) {
+	g, n := buildGraph(t, "s a 1, a b 1, b c -2, c a -1, c d 1")
+	err := runAlgorithm(t, &BellmanFord{}, g, n["s"])
+	if err == nil || !strings.Contains(err.Error(), "cost -2") {
+		t.Fatalf("expected the negative cycle with cost -2, got %v", err)
+	}
+	for _, name := range []string{"a", "b", "c"} {
+		if !n[name].Data.Highlighted {
+			t.Errorf("%s is on the cycle but not highlighted", name)
+		}
+	}
+	for _, name := range []string{"s", "d"} {
+		if n[name].Data.Highlighted {
+			t.Errorf("%s is not on the cycle but highlighted", name)
+		}
+	}
+	if !g.EdgeBetween(n["c"], n["a"]).Data.Highlighted || g.EdgeBetween(n["s"], n["a"]).Data.Highlighted {
+		t.Error("only the edges of the cycle should be highlighted")
+	}
+}
+
+func TestBellmanFordNeedsStart(t *testing.T) {
+	g, _ := buildGraph(t, "a b 1")
+	if err := runAlgorithm(t, &BellmanFord{}, g); err == nil {
+		t.Fatal("expected an error without a starting node")
+	}
+}
@@ -5,9 +5,15 @@ import (
 	"graphographic/graph"
 )
 
+// Breadth first search from the start node, nodes are visited layer by layer in the
+// order they were discovered. Every node is tagged with its layer, the number of
+// edges on the shortest path from the start
 type BFS struct {
 	start *graph.Node
-	stack []*graph.Node
+	queue []*graph.Node
+	layer map[*graph.Node]int
+	// nodes in the order they were visited
+	visited []*graph.Node
 }
 
 func (algo *BFS) Init() {
@@ -20,29 +26,52 @@ func (algo *BFS) Start(g *graph.Graph) error {
 	if algo.start == nil {
 		return fmt.Errorf("Starting node was not selected")
 	}
-	algo.stack = make([]*graph.Node, 0)
-	algo.stack = append(algo.stack, algo.start)
+	algo.queue = append(algo.queue[:0], algo.start)
+	algo.layer = map[*graph.Node]int{algo.start: 0}
+	algo.visited = algo.visited[:0]
+	algo.start.Data.Tag = "0"
 	return nil
 }
 
-func (algo *BFS) Update() bool {
-	var next *graph.Node = nil
-	if len(algo.stack) > 0 {
-		next, algo.stack = algo.stack[len(algo.stack)-1], algo.stack[:len(algo.stack)-1]
-		algo.addNodesToStack(next)
-		return true
-	} else {
-		return false
+func (algo *BFS) Update() (bool, error) {
+	if len(algo.queue) == 0 {
+		return false, nil
 	}
+	var next *graph.Node
+	next, algo.queue = algo.queue[0], algo.queue[1:]
+	algo.visit(next)
+	return len(algo.queue) > 0, nil
 }
 
-func (algo *BFS) addNodesToStack(node *graph.Node) {
+// the nodes in visit order and the edges they were discovered by
+func (algo *BFS) Result() *Result {
+	if algo.start == nil || len(algo.visited) == 0 || len(algo.queue) > 0 {
+		return nil
+	}
+	result := &Result{Nodes: algo.visited}
+	for _, n := range algo.visited {
+		for e := range n.Out() {
+			if e.Data.Explored {
+				result.Edges = append(result.Edges, e)
+			}
+		}
+	}
+	layers := algo.layer[algo.visited[len(algo.visited)-1]]
+	result.Summary = fmt.Sprintf("Visited %d nodes in %d layers", len(algo.visited), layers+1)
+	result.Metrics = []Metric{{"Visited", float64(len(algo.visited))}, {"Layers", float64(layers + 1)}}
+	return result
+}
+
+// Marks the node as visited and queues its undiscovered neighbours one layer further
+func (algo *BFS) visit(node *graph.Node) {
 	node.Data.Explored = true
-	for edgeIt := node.Edges.Front(); edgeIt != nil; edgeIt = edgeIt.Next() {
-		e := edgeIt.Value.(*graph.Edge)
-		if e.Tail == node && !e.Head.Data.Explored {
+	algo.visited = append(algo.visited, node)
+	for e := range node.Out() {
+		if _, discovered := algo.layer[e.Head]; !discovered {
 			e.Data.Explored = true
-			algo.stack = append(algo.stack, e.Head)
+			algo.layer[e.Head] = algo.layer[node] + 1
+			e.Head.Data.Tag = fmt.Sprint(algo.layer[e.Head])
+			algo.queue = append(algo.queue, e.Head)
 		}
 	}
 }
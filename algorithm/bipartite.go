@@ -0,0 +1,145 @@
+package algorithm
+
+import (
+	"fmt"
+	"graphographic/graph"
+	"slices"
+)
+
+// link a node was reached by in the breadth first search
+type bipartiteVisit struct {
+	parent *graph.Node
+	link   undirectedLink
+}
+
+// 2-colouring with a breadth first search, edge directions are ignored. Every
+// update colours the neighbours of one node with the other of the two groups,
+// an edge between nodes of the same group closes an odd cycle which is shown
+type Bipartite struct {
+	graph    *graph.Graph
+	nodes    []*graph.Node
+	links    map[*graph.Node][]undirectedLink
+	visits   map[*graph.Node]bipartiteVisit
+	queue    []*graph.Node
+	nextRoot int
+	sizes    [2]int
+	done     bool
+}
+
+func (algo *Bipartite) Init() {
+	algo.done = false
+}
+
+func (algo *Bipartite) GetName() string {
+	return "Bipartite"
+}
+
+// the whole graph is coloured, nothing has to be selected
+func (algo *Bipartite) NodeSelected(node *graph.Node) {}
+func (algo *Bipartite) UndoSelect()                   {}
+
+func (algo *Bipartite) Start(g *graph.Graph) error {
+	if g.NodeCount() == 0 {
+		return fmt.Errorf("The graph has no nodes")
+	}
+	algo.graph = g
+	algo.nodes = algo.nodes[:0]
+	for n := range g.Nodes() {
+		algo.nodes = append(algo.nodes, n)
+	}
+	algo.links = undirectedLinks(g)
+	algo.visits = make(map[*graph.Node]bipartiteVisit, g.NodeCount())
+	algo.queue = algo.queue[:0]
+	algo.nextRoot = 0
+	algo.sizes = [2]int{}
+	algo.done = false
+	return nil
+}
+
+func (algo *Bipartite) colour(n *graph.Node, group int, visit bipartiteVisit) {
+	n.Data.Group = group
+	algo.sizes[group-1]++
+	algo.visits[n] = visit
+	algo.queue = append(algo.queue, n)
+}
+
+func (algo *Bipartite) Update() (bool, error) {
+	if len(algo.queue) == 0 {
+		for ; algo.nextRoot < len(algo.nodes); algo.nextRoot++ {
+			if _, seen := algo.visits[algo.nodes[algo.nextRoot]]; !seen {
+				n := algo.nodes[algo.nextRoot]
+				algo.colour(n, 1, bipartiteVisit{})
+				return true, nil
+			}
+		}
+		algo.done = true
+		return false, nil
+	}
+	n := algo.queue[0]
+	algo.queue = algo.queue[1:]
+	n.Data.Explored = true
+	for _, link := range algo.links[n] {
+		if _, seen := algo.visits[link.other]; !seen {
+			markUndirected(link.edge, true, false)
+			algo.colour(link.other, 3-n.Data.Group, bipartiteVisit{n, link})
+		} else if link.other.Data.Group == n.Data.Group {
+			return false, algo.oddCycle(n, link)
+		}
+	}
+	return true, nil
+}
+
+// The search paths from both ends of link up to where they meet and the link
+// itself form a cycle, odd since both ends are the same distance from the root
+func (algo *Bipartite) oddCycle(n *graph.Node, link undirectedLink) error {
+	depth := func(n *graph.Node) int {
+		d := 0
+		for ; algo.visits[n].parent != nil; n = algo.visits[n].parent {
+			d++
+		}
+		return d
+	}
+	a, b := n, link.other
+	aNodes, aEdges := []*graph.Node{}, []*graph.Edge{}
+	bNodes, bEdges := []*graph.Node{}, []*graph.Edge{}
+	for da, db := depth(a), depth(b); da > db; da-- {
+		aNodes, aEdges = append(aNodes, a), append(aEdges, algo.visits[a].link.edge)
+		a = algo.visits[a].parent
+	}
+	for da, db := depth(a), depth(b); db > da; db-- {
+		bNodes, bEdges = append(bNodes, b), append(bEdges, algo.visits[b].link.edge)
+		b = algo.visits[b].parent
+	}
+	for a != b {
+		aNodes, aEdges = append(aNodes, a), append(aEdges, algo.visits[a].link.edge)
+		bNodes, bEdges = append(bNodes, b), append(bEdges, algo.visits[b].link.edge)
+		a, b = algo.visits[a].parent, algo.visits[b].parent
+	}
+	// the cycle goes from n up to where the paths meet, down to the other end and
+	// back to n over link
+	nodes := append(aNodes, a)
+	edges := aEdges
+	slices.Reverse(bNodes)
+	slices.Reverse(bEdges)
+	nodes = append(nodes, bNodes...)
+	edges = append(edges, bEdges...)
+	edges = append(edges, link.edge)
+	return newUndirectedCycleError(algo.graph, "The graph is not bipartite, odd cycle", nodes, edges)
+}
+
+func (algo *Bipartite) Result() *Result {
+	if !algo.done {
+		return nil
+	}
+	result := &Result{
+		Summary: fmt.Sprintf("Bipartite: %d and %d nodes", algo.sizes[0], algo.sizes[1]),
+		Metrics: []Metric{{"First group", float64(algo.sizes[0])}, {"Second group", float64(algo.sizes[1])}},
+	}
+	// the first group, the other one is everything else
+	for _, n := range algo.nodes {
+		if n.Data.Group == 1 {
+			result.Nodes = append(result.Nodes, n)
+		}
+	}
+	return result
+}
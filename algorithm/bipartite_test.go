@@ -0,0 +1,89 @@
+package algorithm
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBipartite(t *testing.T) {
+	// a square with a tail, and a lone node
+	g, n := buildGraph(t, undirected("a b, b c, c d, d a, d e")+", x")
+	algo := &Bipartite{}
+	if err := runAlgorithm(t, algo, g); err != nil {
+		t.Fatal(err)
+	}
+	if n["a"].Data.Group != n["c"].Data.Group || n["a"].Data.Group == n["b"].Data.Group || n["e"].Data.Group != n["a"].Data.Group {
+		t.Errorf("groups of a b c e: %d %d %d %d", n["a"].Data.Group, n["b"].Data.Group, n["c"].Data.Group, n["e"].Data.Group)
+	}
+	if got := algo.Result().Summary; got != "Bipartite: 4 and 2 nodes" {
+		t.Errorf("report is %q", got)
+	}
+}
+
+func TestBipartiteOddCycle(t *testing.T) {
+	for _, edges := range []string{
+		undirected("a b, b c, c a"),
+		// the pentagon hangs off a path, so the cycle does not include the root
+		undirected("r s, s a, a b, b c, c d, d e, e a"),
+	} {
+		g, _ := buildGraph(t, edges)
+		err := runAlgorithm(t, &Bipartite{}, g)
+		var cycle *CycleError
+		if !errors.As(err, &cycle) {
+			t.Fatalf("%s: got %v, want a cycle error", edges, err)
+		}
+		if len(cycle.Nodes)%2 != 1 || len(cycle.Nodes) != len(cycle.Edges) {
+			t.Fatalf("%s: cycle %v is not odd", edges, err)
+		}
+		for i, e := range cycle.Edges {
+			a, b := cycle.Nodes[i], cycle.Nodes[(i+1)%len(cycle.Nodes)]
+			if !(e.Tail == a && e.Head == b) && !(e.Tail == b && e.Head == a) {
+				t.Fatalf("%s: edge %d of the cycle %v does not join %s and %s", edges, i, err, a.Content, b.Content)
+			}
+		}
+	}
+}
+
+func TestHopcroftKarp(t *testing.T) {
+	// a greedy matching of a-1, b-2 has to be undone to match c as well
+	g, n := buildGraph(t, "a 1, a 2, b 2, b 3, c 1, d 3, x y, y z")
+	algo := &HopcroftKarp{}
+	if err := runAlgorithm(t, algo, g); err != nil {
+		t.Fatal(err)
+	}
+	if got := algo.Result().Summary; got != "Maximum matching: 4 pairs" {
+		t.Errorf("report is %q", got)
+	}
+	matched := 0
+	for node := range g.Nodes() {
+		count := 0
+		for e := range node.Out() {
+			if e.Data.Explored {
+				count++
+			}
+		}
+		for e := range node.In() {
+			if e.Data.Explored {
+				count++
+			}
+		}
+		if count > 1 {
+			t.Errorf("%s is matched %d times", node.Content, count)
+		}
+		matched += count
+	}
+	if matched != 8 {
+		t.Errorf("%d edges are matched, want 4", matched/2)
+	}
+	if n["a"].Data.Group == n["1"].Data.Group {
+		t.Error("a and 1 are in the same group")
+	}
+}
+
+func TestHopcroftKarpNotBipartite(t *testing.T) {
+	g, _ := buildGraph(t, undirected("a b, b c, c a"))
+	var cycle *CycleError
+	if err := runAlgorithm(t, &HopcroftKarp{}, g); !errors.As(err, &cycle) {
+		t.Fatalf("got %v, want a cycle error", err)
+	}
+}
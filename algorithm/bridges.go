@@ -0,0 +1,152 @@
+package algorithm
+
+import (
+	"fmt"
+	"graphographic/graph"
+)
+
+type bridgesFrame struct {
+	node *graph.Node
+	// edge the node was reached by, not followed back to the parent
+	via      *graph.Edge
+	next     int
+	children int
+}
+
+// Bridges and articulation points, the edges and nodes whose removal splits the
+// graph, found with Tarjan's lowlink on the graph with edge directions ignored.
+// Every update follows one edge of the depth first search or finishes one node,
+// nodes are tagged with their discovery time and lowlink. At the end the bridges
+// and articulation points are highlighted
+type Bridges struct {
+	nodes    []*graph.Node
+	links    map[*graph.Node][]undirectedLink
+	disc     map[*graph.Node]int
+	low      map[*graph.Node]int
+	calls    []bridgesFrame
+	nextRoot int
+	bridges  []*graph.Edge
+	cuts     map[*graph.Node]bool
+	done     bool
+}
+
+func (algo *Bridges) Init() {
+	algo.done = false
+}
+
+func (algo *Bridges) GetName() string {
+	return "Bridges"
+}
+
+// the whole graph is checked, nothing has to be selected
+func (algo *Bridges) NodeSelected(node *graph.Node) {}
+func (algo *Bridges) UndoSelect()                   {}
+
+func (algo *Bridges) Start(g *graph.Graph) error {
+	if g.NodeCount() == 0 {
+		return fmt.Errorf("The graph has no nodes")
+	}
+	algo.nodes = algo.nodes[:0]
+	for n := range g.Nodes() {
+		algo.nodes = append(algo.nodes, n)
+	}
+	algo.links = undirectedLinks(g)
+	algo.disc = make(map[*graph.Node]int, g.NodeCount())
+	algo.low = make(map[*graph.Node]int, g.NodeCount())
+	algo.calls = algo.calls[:0]
+	algo.nextRoot = 0
+	algo.bridges = nil
+	algo.cuts = make(map[*graph.Node]bool)
+	algo.done = false
+	return nil
+}
+
+func (algo *Bridges) visit(n *graph.Node, via *graph.Edge) {
+	algo.disc[n] = len(algo.disc)
+	algo.low[n] = algo.disc[n]
+	algo.calls = append(algo.calls, bridgesFrame{node: n, via: via})
+	n.Data.Explored = true
+	algo.tag(n)
+}
+
+func (algo *Bridges) tag(n *graph.Node) {
+	n.Data.Tag = fmt.Sprintf("d=%d low=%d", algo.disc[n], algo.low[n])
+}
+
+func (algo *Bridges) Update() (bool, error) {
+	if len(algo.calls) == 0 {
+		for ; algo.nextRoot < len(algo.nodes); algo.nextRoot++ {
+			if _, seen := algo.disc[algo.nodes[algo.nextRoot]]; !seen {
+				algo.visit(algo.nodes[algo.nextRoot], nil)
+				return true, nil
+			}
+		}
+		algo.finish()
+		return false, nil
+	}
+	top := &algo.calls[len(algo.calls)-1]
+	v := top.node
+	if links := algo.links[v]; top.next < len(links) {
+		link := links[top.next]
+		top.next++
+		if link.edge == top.via {
+			return true, nil
+		}
+		if _, seen := algo.disc[link.other]; !seen {
+			top.children++
+			markUndirected(link.edge, true, false)
+			algo.visit(link.other, link.edge)
+		} else {
+			algo.low[v] = min(algo.low[v], algo.disc[link.other])
+			algo.tag(v)
+		}
+		return true, nil
+	}
+	frame := *top
+	algo.calls = algo.calls[:len(algo.calls)-1]
+	if len(algo.calls) == 0 {
+		// a root splits the graph when the search had to leave it more than once
+		if frame.children > 1 {
+			algo.cuts[v] = true
+		}
+		return true, nil
+	}
+	parent := algo.calls[len(algo.calls)-1].node
+	algo.low[parent] = min(algo.low[parent], algo.low[v])
+	algo.tag(parent)
+	// nothing below v reaches above the parent without the edge between them
+	if algo.low[v] > algo.disc[parent] {
+		algo.bridges = append(algo.bridges, frame.via)
+	}
+	if algo.low[v] >= algo.disc[parent] && len(algo.calls) > 1 {
+		algo.cuts[parent] = true
+	}
+	return true, nil
+}
+
+func (algo *Bridges) finish() {
+	for _, n := range algo.nodes {
+		n.Data.Highlighted = algo.cuts[n]
+	}
+	for _, e := range algo.bridges {
+		markUndirected(e, true, true)
+	}
+	algo.done = true
+}
+
+func (algo *Bridges) Result() *Result {
+	if !algo.done {
+		return nil
+	}
+	result := &Result{
+		Summary: fmt.Sprintf("%d bridges, %d articulation points", len(algo.bridges), len(algo.cuts)),
+		Metrics: []Metric{{"Bridges", float64(len(algo.bridges))}, {"Articulation points", float64(len(algo.cuts))}},
+		Edges:   algo.bridges,
+	}
+	for _, n := range algo.nodes {
+		if algo.cuts[n] {
+			result.Nodes = append(result.Nodes, n)
+		}
+	}
+	return result
+}
@@ -0,0 +1,40 @@
+package algorithm
+
+import (
+	"testing"
+)
+
+func TestBridges(t *testing.T) {
+	// two triangles joined by the bridge c-d, e hangs off d through a directed edge
+	g, n := buildGraph(t, undirected("a b 1, b c 1, c a 1, c d 1, d f 1, f g 1, g d 1")+", d e 1, x")
+	algo := &Bridges{}
+	if err := runAlgorithm(t, algo, g); err != nil {
+		t.Fatal(err)
+	}
+	for name, cut := range map[string]bool{"a": false, "b": false, "c": true, "d": true, "e": false, "f": false, "x": false} {
+		if n[name].Data.Highlighted != cut {
+			t.Errorf("%s highlighted: %v, want %v", name, n[name].Data.Highlighted, cut)
+		}
+	}
+	bridges := map[[2]string]bool{{"c", "d"}: true, {"d", "c"}: true, {"d", "e"}: true}
+	for e := range g.Edges() {
+		if want := bridges[[2]string{e.Tail.Content, e.Head.Content}]; e.Data.Highlighted != want {
+			t.Errorf("edge %s -> %s highlighted: %v, want %v", e.Tail.Content, e.Head.Content, e.Data.Highlighted, want)
+		}
+	}
+	if got := algo.Result().Summary; got != "2 bridges, 2 articulation points" {
+		t.Errorf("report is %q", got)
+	}
+}
+
+func TestBridgesParallelEdges(t *testing.T) {
+	// two separate edges between a and b keep each other from being bridges
+	g, n := buildGraph(t, "a b 1, a b 2, b c 1")
+	algo := &Bridges{}
+	if err := runAlgorithm(t, algo, g); err != nil {
+		t.Fatal(err)
+	}
+	if !n["b"].Data.Highlighted || g.EdgeBetween(n["a"], n["b"]).Data.Highlighted {
+		t.Errorf("only b -> c should be a bridge and b an articulation point, got %q", algo.Result().Summary)
+	}
+}
@@ -0,0 +1,255 @@
+package algorithm
+
+import (
+	"cmp"
+	"fmt"
+	"graphographic/graph"
+	"slices"
+)
+
+// Order in which nodes are coloured
+type ColouringStrategy int
+
+const (
+	// nodes in the order they were created, each gets the lowest colour its
+	// neighbours do not have
+	ColouringGreedy ColouringStrategy = iota
+	// nodes by decreasing degree, one colour is given to as many nodes as possible
+	// before the next one is used
+	ColouringWelshPowell
+	// always the node with the most differently coloured neighbours next
+	ColouringDSatur
+	// backtracking search for the lowest number of colours, only for small graphs
+	ColouringExact
+	colouringStrategyCount
+)
+
+// the exact search takes exponential time, larger graphs would never finish
+const exactColouringMaxNodes = 12
+
+func (s ColouringStrategy) String() string {
+	switch s {
+	case ColouringGreedy:
+		return "greedy"
+	case ColouringWelshPowell:
+		return "Welsh-Powell"
+	case ColouringDSatur:
+		return "DSatur"
+	case ColouringExact:
+		return "exact"
+	}
+	return "unknown"
+}
+
+// Colours the nodes so that no two neighbours share a colour, edge directions are
+// ignored. Every update colours one node (or takes a colour back in the exact
+// search), colours are stored in the node group
+type Colouring struct {
+	Strategy   ColouringStrategy
+	nodes      []*graph.Node
+	neighbours map[*graph.Node][]*graph.Node
+	// node coloured by the last update
+	last *graph.Node
+	// position in nodes of the greedy, Welsh-Powell and exact strategies
+	next int
+	// colour handed out by Welsh-Powell, the most colours the exact search may use
+	colour  int
+	left    int
+	colours int
+	done    bool
+}
+
+func (algo *Colouring) Init() {
+	algo.done = false
+}
+
+func (algo *Colouring) GetName() string {
+	return "Colouring"
+}
+
+func (algo *Colouring) NextOption() {
+	algo.Strategy = (algo.Strategy + 1) % colouringStrategyCount
+}
+
+func (algo *Colouring) OptionName() string {
+	return algo.Strategy.String()
+}
+
+// the whole graph is coloured, nothing has to be selected
+func (algo *Colouring) NodeSelected(node *graph.Node) {}
+func (algo *Colouring) UndoSelect()                   {}
+
+func (algo *Colouring) Start(g *graph.Graph) error {
+	if g.NodeCount() == 0 {
+		return fmt.Errorf("The graph has no nodes")
+	}
+	if algo.Strategy == ColouringExact && g.NodeCount() > exactColouringMaxNodes {
+		return fmt.Errorf("Exact colouring works on at most %d nodes", exactColouringMaxNodes)
+	}
+	for e := range g.Edges() {
+		if e.Tail == e.Head {
+			return fmt.Errorf("Node %s has a loop, it can not be coloured", e.Tail.Content)
+		}
+	}
+	algo.neighbours = make(map[*graph.Node][]*graph.Node, g.NodeCount())
+	for n, links := range undirectedLinks(g) {
+		for _, link := range links {
+			algo.neighbours[n] = append(algo.neighbours[n], link.other)
+		}
+	}
+	algo.nodes = algo.nodes[:0]
+	for n := range g.Nodes() {
+		algo.nodes = append(algo.nodes, n)
+	}
+	if algo.Strategy == ColouringWelshPowell || algo.Strategy == ColouringExact {
+		slices.SortStableFunc(algo.nodes, func(a, b *graph.Node) int {
+			return cmp.Compare(len(algo.neighbours[b]), len(algo.neighbours[a]))
+		})
+	}
+	algo.last = nil
+	algo.next = 0
+	algo.colour = 1
+	algo.left = len(algo.nodes)
+	algo.colours = 0
+	algo.done = false
+	return nil
+}
+
+// whether a neighbour of n already has the colour
+func (algo *Colouring) conflicts(n *graph.Node, colour int) bool {
+	for _, m := range algo.neighbours[n] {
+		if m.Data.Group == colour {
+			return true
+		}
+	}
+	return false
+}
+
+func (algo *Colouring) lowestFree(n *graph.Node) int {
+	colour := 1
+	for algo.conflicts(n, colour) {
+		colour++
+	}
+	return colour
+}
+
+// number of different colours among the neighbours of n
+func (algo *Colouring) saturation(n *graph.Node) int {
+	seen := make(map[int]bool)
+	for _, m := range algo.neighbours[n] {
+		if m.Data.Group != 0 {
+			seen[m.Data.Group] = true
+		}
+	}
+	return len(seen)
+}
+
+func (algo *Colouring) paint(n *graph.Node, colour int) {
+	n.Data.Group = colour
+	n.Data.Highlighted = true
+	n.Data.Tag = fmt.Sprint(colour)
+	algo.last = n
+	algo.left--
+	algo.colours = max(algo.colours, colour)
+}
+
+func (algo *Colouring) Update() (bool, error) {
+	if algo.last != nil {
+		algo.last.Data.Highlighted = false
+		algo.last = nil
+	}
+	if algo.Strategy == ColouringExact {
+		return algo.exactStep(), nil
+	}
+	if algo.left == 0 {
+		algo.done = true
+		return false, nil
+	}
+	switch algo.Strategy {
+	case ColouringGreedy:
+		n := algo.nodes[algo.next]
+		algo.next++
+		algo.paint(n, algo.lowestFree(n))
+	case ColouringWelshPowell:
+		for {
+			for ; algo.next < len(algo.nodes); algo.next++ {
+				n := algo.nodes[algo.next]
+				if n.Data.Group == 0 && !algo.conflicts(n, algo.colour) {
+					algo.paint(n, algo.colour)
+					return true, nil
+				}
+			}
+			algo.colour++
+			algo.next = 0
+		}
+	case ColouringDSatur:
+		var best *graph.Node
+		bestSaturation := -1
+		for _, n := range algo.nodes {
+			if n.Data.Group != 0 {
+				continue
+			}
+			s := algo.saturation(n)
+			if s > bestSaturation || s == bestSaturation && len(algo.neighbours[n]) > len(algo.neighbours[best]) {
+				best, bestSaturation = n, s
+			}
+		}
+		algo.paint(best, algo.lowestFree(best))
+	}
+	return true, nil
+}
+
+// One step of the search for a colouring with at most algo.colour colours, the
+// node at algo.next gets its next possible colour or, when there is none, gives
+// its colour back and the search goes back a node. When it goes back past the
+// first node the search starts over with one colour more
+func (algo *Colouring) exactStep() bool {
+	if algo.next == len(algo.nodes) {
+		algo.done = true
+		return false
+	}
+	n := algo.nodes[algo.next]
+	// colours are interchangeable, a node never needs a colour higher than one
+	// above those of the nodes before it
+	limit := 1
+	for _, m := range algo.nodes[:algo.next] {
+		limit = max(limit, m.Data.Group+1)
+	}
+	limit = min(limit, algo.colour)
+	colour := n.Data.Group + 1
+	for colour <= limit && algo.conflicts(n, colour) {
+		colour++
+	}
+	if colour <= limit {
+		n.Data.Group = colour
+		n.Data.Highlighted = true
+		n.Data.Tag = fmt.Sprint(colour)
+		algo.last = n
+		algo.next++
+		return true
+	}
+	n.Data.Group = 0
+	n.Data.Tag = ""
+	algo.next--
+	if algo.next < 0 {
+		algo.colour++
+		algo.next = 0
+	}
+	return true
+}
+
+func (algo *Colouring) Result() *Result {
+	if !algo.done {
+		return nil
+	}
+	if algo.Strategy == ColouringExact {
+		return &Result{
+			Summary: fmt.Sprintf("Chromatic number: %d", algo.colour),
+			Metrics: []Metric{{"Colours", float64(algo.colour)}},
+		}
+	}
+	return &Result{
+		Summary: fmt.Sprintf("%d colours", algo.colours),
+		Metrics: []Metric{{"Colours", float64(algo.colours)}},
+	}
+}
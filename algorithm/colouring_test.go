@@ -0,0 +1,62 @@
+package algorithm
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// crown graph, greedy colouring in creation order needs a colour per pair
+const crown = "a1 b2, a1 b3, b1 a2, b1 a3, a2 b3, b2 a3"
+
+func TestColouringIsProper(t *testing.T) {
+	graphs := []string{undirected(crown), undirected("a b, b c, c d, d e, e a, a c, x y"), "a b, b c, c a, z"}
+	for _, edges := range graphs {
+		for strategy := range colouringStrategyCount {
+			g, _ := buildGraph(t, edges)
+			algo := &Colouring{Strategy: strategy}
+			if err := runAlgorithm(t, algo, g); err != nil {
+				t.Fatalf("%v on %q: %v", strategy, edges, err)
+			}
+			for e := range g.Edges() {
+				if e.Tail.Data.Group == 0 || e.Tail.Data.Group == e.Head.Data.Group {
+					t.Errorf("%v on %q: %s and %s both have colour %d", strategy, edges, e.Tail.Content, e.Head.Content, e.Head.Data.Group)
+				}
+			}
+		}
+	}
+}
+
+func TestColouringCounts(t *testing.T) {
+	for _, test := range []struct {
+		edges    string
+		strategy ColouringStrategy
+		report   string
+	}{
+		{undirected(crown), ColouringGreedy, "3 colours"},
+		{undirected(crown), ColouringExact, "Chromatic number: 2"},
+		{undirected("a b, b c, c d, d e, e a"), ColouringDSatur, "3 colours"},
+		{undirected("a b, b c, c d, d e, e a"), ColouringExact, "Chromatic number: 3"},
+		{"a, b, c", ColouringExact, "Chromatic number: 1"},
+	} {
+		g, _ := buildGraph(t, test.edges)
+		algo := &Colouring{Strategy: test.strategy}
+		if err := runAlgorithm(t, algo, g); err != nil {
+			t.Fatal(err)
+		}
+		if got := algo.Result().Summary; got != test.report {
+			t.Errorf("%v on %q: report is %q, want %q", test.strategy, test.edges, got, test.report)
+		}
+	}
+}
+
+func TestExactColouringGuard(t *testing.T) {
+	nodes := make([]string, exactColouringMaxNodes+1)
+	for i := range nodes {
+		nodes[i] = fmt.Sprint(i)
+	}
+	g, _ := buildGraph(t, strings.Join(nodes, ", "))
+	if err := runAlgorithm(t, &Colouring{Strategy: ColouringExact}, g); err == nil {
+		t.Fatal("exact colouring started on a graph over the limit")
+	}
+}
@@ -0,0 +1,62 @@
+package algorithm
+
+import (
+	"graphographic/graph"
+	"strings"
+)
+
+// Error of an algorithm that ran into a cycle it can not handle, the cycle is
+// highlighted in the graph
+type CycleError struct {
+	// why the cycle is a problem
+	Reason string
+	// Edges[i] goes from Nodes[i] to the next node of the cycle, against its
+	// direction when the algorithm ignores directions
+	Nodes []*graph.Node
+	Edges []*graph.Edge
+}
+
+func (err *CycleError) Error() string {
+	names := make([]string, 0, len(err.Nodes)+1)
+	for _, n := range err.Nodes {
+		names = append(names, n.Content)
+	}
+	if len(err.Nodes) > 0 {
+		names = append(names, err.Nodes[0].Content)
+	}
+	return err.Reason + ": " + strings.Join(names, " -> ")
+}
+
+// Make the error for the cycle formed by edges, which must follow each other,
+// and highlight it as the only thing in the graph
+func newCycleError(g *graph.Graph, reason string, edges []*graph.Edge) *CycleError {
+	clearHighlights(g)
+	err := &CycleError{Reason: reason, Edges: edges}
+	for _, e := range edges {
+		e.Data.Highlighted = true
+		e.Tail.Data.Highlighted = true
+		err.Nodes = append(err.Nodes, e.Tail)
+	}
+	return err
+}
+
+// Like newCycleError for a cycle that may use edges against their direction
+func newUndirectedCycleError(g *graph.Graph, reason string, nodes []*graph.Node, edges []*graph.Edge) *CycleError {
+	clearHighlights(g)
+	for _, n := range nodes {
+		n.Data.Highlighted = true
+	}
+	for _, e := range edges {
+		markUndirected(e, e.Data.Explored, true)
+	}
+	return &CycleError{Reason: reason, Nodes: nodes, Edges: edges}
+}
+
+func clearHighlights(g *graph.Graph) {
+	for n := range g.Nodes() {
+		n.Data.Highlighted = false
+	}
+	for e := range g.Edges() {
+		e.Data.Highlighted = false
+	}
+}
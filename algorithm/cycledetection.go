@@ -0,0 +1,169 @@
+package algorithm
+
+import (
+	"fmt"
+	"graphographic/graph"
+)
+
+// node on the recursion stack, the edge it was reached by and how many of its
+// links were followed
+type cycleFrame struct {
+	node *graph.Node
+	edge *graph.Edge
+	next int
+}
+
+// Depth first search that stops at the first cycle, a graph made only of undirected
+// edge pairs is treated as undirected. In a mixed graph the edges are followed in
+// their direction, but never back over the pair of the edge a node was reached by.
+// The recursion stack is highlighted, finished
+// nodes are explored. An edge to a node that is still on the stack closes a cycle,
+// which is highlighted on its own at the end
+type CycleDetection struct {
+	graph      *graph.Graph
+	undirected bool
+	nodes      []*graph.Node
+	links      map[*graph.Node][]undirectedLink
+	colours    map[*graph.Node]dfsColour
+	stack      []cycleFrame
+	nextRoot   int
+	cycle      *CycleError
+	done       bool
+}
+
+func (algo *CycleDetection) Init() {
+	algo.done = false
+}
+
+func (algo *CycleDetection) GetName() string {
+	return "Cycle detection"
+}
+
+// the whole graph is searched, nothing has to be selected
+func (algo *CycleDetection) NodeSelected(node *graph.Node) {}
+func (algo *CycleDetection) UndoSelect()                   {}
+
+func (algo *CycleDetection) Start(g *graph.Graph) error {
+	if g.NodeCount() == 0 {
+		return fmt.Errorf("The graph has no nodes")
+	}
+	algo.graph = g
+	algo.undirected = g.IsUndirected()
+	algo.links = make(map[*graph.Node][]undirectedLink, g.NodeCount())
+	if algo.undirected {
+		for _, e := range undirectedEdges(g) {
+			algo.links[e.Tail] = append(algo.links[e.Tail], undirectedLink{e, e.Head})
+			if e.Tail != e.Head {
+				algo.links[e.Head] = append(algo.links[e.Head], undirectedLink{e, e.Tail})
+			}
+		}
+	} else {
+		for e := range g.Edges() {
+			algo.links[e.Tail] = append(algo.links[e.Tail], undirectedLink{e, e.Head})
+		}
+	}
+	algo.nodes = algo.nodes[:0]
+	for n := range g.Nodes() {
+		algo.nodes = append(algo.nodes, n)
+	}
+	algo.colours = make(map[*graph.Node]dfsColour, g.NodeCount())
+	algo.stack = algo.stack[:0]
+	algo.nextRoot = 0
+	algo.cycle = nil
+	algo.done = false
+	return nil
+}
+
+func (algo *CycleDetection) mark(e *graph.Edge, explored, highlighted bool) {
+	if algo.undirected {
+		markUndirected(e, explored, highlighted)
+	} else {
+		e.Data.Explored, e.Data.Highlighted = explored, highlighted
+	}
+}
+
+func (algo *CycleDetection) push(n *graph.Node, e *graph.Edge) {
+	algo.colours[n] = dfsGrey
+	n.Data.Highlighted = true
+	if e != nil {
+		algo.mark(e, false, true)
+	}
+	algo.stack = append(algo.stack, cycleFrame{node: n, edge: e})
+}
+
+func (algo *CycleDetection) Update() (bool, error) {
+	if len(algo.stack) == 0 {
+		for ; algo.nextRoot < len(algo.nodes); algo.nextRoot++ {
+			if n := algo.nodes[algo.nextRoot]; algo.colours[n] == dfsWhite {
+				algo.push(n, nil)
+				return true, nil
+			}
+		}
+		algo.done = true
+		return false, nil
+	}
+	top := &algo.stack[len(algo.stack)-1]
+	for top.next < len(algo.links[top.node]) {
+		link := algo.links[top.node][top.next]
+		top.next++
+		// going back over the edge the node was reached by is no cycle
+		if top.edge != nil && (link.edge == top.edge || link.edge == top.edge.Pair()) {
+			continue
+		}
+		switch algo.colours[link.other] {
+		case dfsWhite:
+			algo.push(link.other, link.edge)
+			return true, nil
+		case dfsGrey:
+			algo.closeCycle(link)
+			algo.done = true
+			return false, nil
+		}
+	}
+	// finished
+	algo.stack = algo.stack[:len(algo.stack)-1]
+	algo.colours[top.node] = dfsBlack
+	top.node.Data.Highlighted = false
+	top.node.Data.Explored = true
+	if top.edge != nil {
+		algo.mark(top.edge, true, false)
+	}
+	return true, nil
+}
+
+// the stack from link.other up to the top and link back to it
+func (algo *CycleDetection) closeCycle(link undirectedLink) {
+	i := len(algo.stack) - 1
+	for algo.stack[i].node != link.other {
+		i--
+	}
+	nodes := make([]*graph.Node, 0, len(algo.stack)-i)
+	edges := make([]*graph.Edge, 0, len(algo.stack)-i)
+	for j, frame := range algo.stack[i:] {
+		nodes = append(nodes, frame.node)
+		if j > 0 {
+			edges = append(edges, frame.edge)
+		}
+	}
+	edges = append(edges, link.edge)
+	if algo.undirected {
+		algo.cycle = newUndirectedCycleError(algo.graph, "Cycle", nodes, edges)
+	} else {
+		algo.cycle = newCycleError(algo.graph, "Cycle", edges)
+	}
+}
+
+func (algo *CycleDetection) Result() *Result {
+	if !algo.done {
+		return nil
+	}
+	if algo.cycle == nil {
+		return &Result{Summary: "No cycles"}
+	}
+	return &Result{
+		Summary: algo.cycle.Error(),
+		Metrics: []Metric{{"Cycle length", float64(len(algo.cycle.Edges))}},
+		Nodes:   algo.cycle.Nodes,
+		Edges:   algo.cycle.Edges,
+	}
+}
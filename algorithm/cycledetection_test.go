@@ -0,0 +1,47 @@
+package algorithm
+
+import (
+	"testing"
+)
+
+func TestCycleDetection(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		edges  string
+		report string
+	}{
+		// a -> c is a forward edge and d -> c a cross edge, neither closes a cycle
+		{"directed acyclic", "a b, b c, a c, a d, d c", "No cycles"},
+		{"directed", "x a, a b, b c, c d, d b", "Cycle: b -> c -> d -> b"},
+		{"loop", "a b, b b", "Cycle: b -> b"},
+		// two opposite edges that are no undirected pair
+		{"directed two way", "a b, b a", "Cycle: a -> b -> a"},
+		// only the edge pair leads back from b
+		{"mixed acyclic", "a - b, b c, a c, c d", "No cycles"},
+		{"mixed", "a - b, b c, c a", "Cycle: a -> b -> c -> a"},
+		// the edge pairs of a tree are no cycles
+		{"undirected tree", undirected("a b, b c, b d"), "No cycles"},
+		{"undirected", undirected("a b, b c, c d, d b"), "Cycle: b -> c -> d -> b"},
+	} {
+		g, _ := buildGraph(t, test.edges)
+		algo := &CycleDetection{}
+		if err := runAlgorithm(t, algo, g); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if got := algo.Result().Summary; got != test.report {
+			t.Errorf("%s: report is %q, want %q", test.name, got, test.report)
+		}
+		highlighted := 0
+		for e := range g.Edges() {
+			if e.Data.Highlighted {
+				highlighted++
+			}
+		}
+		if algo.cycle == nil && highlighted > 0 {
+			t.Errorf("%s: %d edges are highlighted without a cycle", test.name, highlighted)
+		}
+		if algo.cycle != nil && highlighted == 0 {
+			t.Errorf("%s: the cycle is not highlighted", test.name)
+		}
+	}
+}
@@ -4,9 +4,42 @@ import (
 	"graphographic/graph"
 )
 
+// state of a node in the depth first search
+type dfsColour int
+
+const (
+	// not visited yet
+	dfsWhite dfsColour = iota
+	// on the recursion stack
+	dfsGrey
+	// finished, everything reachable from it was searched
+	dfsBlack
+)
+
+// node on the recursion stack and how many of its edges were followed
+type dfsFrame struct {
+	node  *graph.Node
+	edges []*graph.Edge
+	next  int
+}
+
+// Depth first search from the start node in the order a recursive search takes.
+// Every update follows one edge or finishes a node, the recursion stack is
+// highlighted. Nodes are tagged with their discovery and finish times, edges with
+// their kind: tree, back (to a node on the stack), forward (to a finished
+// descendant) or cross (to any other finished node)
 type DFS struct {
-	start *graph.Node
-	queue []*graph.Node
+	start   *graph.Node
+	stack   []dfsFrame
+	colours map[*graph.Node]dfsColour
+	// discovery and finish times
+	discovered map[*graph.Node]int
+	finished   map[*graph.Node]int
+	time       int
+	// nodes in the order they were discovered
+	visited []*graph.Node
+	// edges that discovered a node
+	tree []*graph.Edge
 }
 func (algo *DFS) Init() {
 	algo.start = nil
@@ -18,30 +51,76 @@ func (algo *DFS) Start(g *graph.Graph) error {
 	if algo.start == nil {
 		return fmt.Errorf("Starting node was not selected")
 	}
-	algo.queue = make([]*graph.Node, 0)
-	algo.queue = append(algo.queue, algo.start)
+	algo.stack = algo.stack[:0]
+	algo.colours = make(map[*graph.Node]dfsColour)
+	algo.discovered = make(map[*graph.Node]int)
+	algo.finished = make(map[*graph.Node]int)
+	algo.time = 0
+	algo.visited = algo.visited[:0]
+	algo.tree = algo.tree[:0]
+	algo.discover(algo.start)
 	return nil
 }
 
-func (algo *DFS) Update() bool {
-	var next *graph.Node = nil
-	if len(algo.queue) > 0 {
-		next, algo.queue = algo.queue[0], algo.queue[1:]
-		algo.addNodesToQueue(next)
-		return true
-	} else {
-		return false
+func (algo *DFS) discover(node *graph.Node) {
+	algo.time++
+	algo.colours[node] = dfsGrey
+	algo.discovered[node] = algo.time
+	algo.visited = append(algo.visited, node)
+	node.Data.Highlighted = true
+	node.Data.Tag = fmt.Sprintf("%d/", algo.time)
+	frame := dfsFrame{node: node}
+	for e := range node.Out() {
+		frame.edges = append(frame.edges, e)
 	}
+	algo.stack = append(algo.stack, frame)
 }
 
-func (algo *DFS) addNodesToQueue(node *graph.Node){
-	node.Data.Explored = true
-	for edgeIt := node.Edges.Front(); edgeIt != nil; edgeIt = edgeIt.Next() {
-		e := edgeIt.Value.(*graph.Edge)
-		if e.Tail == node && !e.Head.Data.Explored {
+func (algo *DFS) Update() (bool, error) {
+	if len(algo.stack) == 0 {
+		return false, nil
+	}
+	top := &algo.stack[len(algo.stack)-1]
+	if top.next < len(top.edges) {
+		e := top.edges[top.next]
+		top.next++
+		switch algo.colours[e.Head] {
+		case dfsWhite:
 			e.Data.Explored = true
-			algo.queue = append(algo.queue, e.Head)
+			e.Data.Tag = "tree"
+			algo.tree = append(algo.tree, e)
+			algo.discover(e.Head)
+		case dfsGrey:
+			e.Data.Tag = "back"
+		default:
+			if algo.discovered[e.Tail] < algo.discovered[e.Head] {
+				e.Data.Tag = "forward"
+			} else {
+				e.Data.Tag = "cross"
+			}
 		}
+		return true, nil
+	}
+	algo.time++
+	algo.colours[top.node] = dfsBlack
+	algo.finished[top.node] = algo.time
+	top.node.Data.Highlighted = false
+	top.node.Data.Explored = true
+	top.node.Data.Tag = fmt.Sprintf("%d/%d", algo.discovered[top.node], algo.time)
+	algo.stack = algo.stack[:len(algo.stack)-1]
+	return len(algo.stack) > 0, nil
+}
+
+// the nodes in discovery order and the edges of the search tree
+func (algo *DFS) Result() *Result {
+	if algo.start == nil || len(algo.visited) == 0 || len(algo.stack) > 0 {
+		return nil
+	}
+	return &Result{
+		Summary: fmt.Sprintf("Visited %d nodes", len(algo.visited)),
+		Metrics: []Metric{{"Visited", float64(len(algo.visited))}, {"Tree edges", float64(len(algo.tree))}},
+		Nodes:   algo.visited,
+		Edges:   algo.tree,
 	}
 }
 
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"graphographic/graph"
 	"math"
+	"slices"
 )
 
 type data struct {
@@ -14,15 +15,16 @@ type data struct {
 }
 
 type Dijkstra struct {
-	heap  MinHeap[*graph.Node]
+	heap  *IndexedHeap[int32, *graph.Node]
 	start *graph.Node
 	end   *graph.Node
 	prev  *graph.Node
 	graph *graph.Graph
+	done  bool
 }
 
 func (algo *Dijkstra) Init() {
-	algo.heap = make(MinHeap[*graph.Node], 0)
+	algo.heap = NewIndexedHeap[int32, *graph.Node]()
 	algo.start = nil
 	algo.end = nil
 	algo.prev = nil
@@ -38,6 +40,7 @@ func (algo *Dijkstra) Start(g *graph.Graph) error {
 		return fmt.Errorf("Start or End node not selected")
 	}
 	algo.graph = g
+	algo.done = false
 	startData := new(data)
 	startData.Prev = nil
 	startData.Len = 0
@@ -47,10 +50,10 @@ func (algo *Dijkstra) Start(g *graph.Graph) error {
 	algo.start.Data.Highlighted = false
 	algo.start.Data.Explored = true
 
-	Insert(&algo.heap, 0, algo.start)
+	algo.heap = NewIndexedHeap[int32, *graph.Node]()
+	algo.heap.Push(0, algo.start)
 
-	for nodeIt := g.Nodes.Front(); nodeIt != nil; nodeIt = nodeIt.Next() {
-		n := nodeIt.Value.(*graph.Node)
+	for n := range g.Nodes() {
 		if n == algo.start {
 			continue
 		}
@@ -61,16 +64,14 @@ func (algo *Dijkstra) Start(g *graph.Graph) error {
 		n.Data.Custom = nodeData
 
 		n.Data.Explored = false
-		Insert(&algo.heap, nodeData.Len, n)
+		algo.heap.Push(nodeData.Len, n)
 	}
 	return nil
 }
 
-func (algo *Dijkstra) Update() bool {
-	if Len(&algo.heap) > 0 {
-		k, n := GetMin(&algo.heap)
-		next := *n
-		Pop(&algo.heap)
+func (algo *Dijkstra) Update() (bool, error) {
+	if algo.heap.Len() > 0 {
+		k, next := algo.heap.Pop()
 		nextNodeData := next.Data.Custom.(*data)
 		next.Data.Explored = true
 		nextNodeData.InPath = true
@@ -80,29 +81,23 @@ func (algo *Dijkstra) Update() bool {
 		} else {
 			next.Data.Tag = fmt.Sprintf("%d", nextNodeData.Len)
 		}
-		for edgeIt := next.Edges.Front(); edgeIt != nil; edgeIt = edgeIt.Next() {
-			edge := edgeIt.Value.(*graph.Edge)
+		for edge := range next.Out() {
 			headData := edge.Head.Data.Custom.(*data)
 			if !headData.InPath && nextNodeData.Len != math.MaxInt32{
-				currentCost, _ := Search(&algo.heap, edge.Head)
-				if currentCost > nextNodeData.Len+edge.Cost {
-					Delete(&algo.heap, edge.Head)
-					d2 := edge.Head.Data.Custom.(*data)
-					d2.Len = nextNodeData.Len + edge.Cost
+				if algo.heap.DecreaseKey(edge.Head, nextNodeData.Len+edge.Cost) {
+					headData.Len = nextNodeData.Len + edge.Cost
 					headData.Prev = next
 					headData.PrevEdge = edge
-					Insert(&algo.heap, d2.Len, edge.Head)
 				}
 			}
 		}
 		if next != algo.end {
-			return true
+			return true, nil
 		}
 		algo.prev = next
 	}
 	if algo.prev == algo.end {
-		for nodeIt := algo.graph.Nodes.Front(); nodeIt != nil; nodeIt = nodeIt.Next() {
-			n := nodeIt.Value.(*graph.Node)
+		for n := range algo.graph.Nodes() {
 			n.Data.Explored = false
 		}
 		for prev := algo.prev; prev != nil; {
@@ -113,8 +108,24 @@ func (algo *Dijkstra) Update() bool {
 			}
 			prev = d.Prev
 		}
+		algo.done = true
 	}
-	return false
+	return false, nil
+}
+
+func (algo *Dijkstra) Result() *Result {
+	if !algo.done {
+		return nil
+	}
+	if algo.end.Data.Custom.(*data).Len == math.MaxInt32 {
+		return &Result{Summary: fmt.Sprintf("%s can not be reached from %s", algo.end.Content, algo.start.Content)}
+	}
+	path := make([]*graph.Edge, 0)
+	for d := algo.end.Data.Custom.(*data); d.PrevEdge != nil; d = d.Prev.Data.Custom.(*data) {
+		path = append(path, d.PrevEdge)
+	}
+	slices.Reverse(path)
+	return pathResult(algo.start, path)
 }
 
 func (algo *Dijkstra) NodeSelected(node *graph.Node) {
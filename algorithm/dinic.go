@@ -0,0 +1,98 @@
+package algorithm
+
+import (
+	"graphographic/graph"
+)
+
+// Maximum flow that sorts nodes into levels by their distance from the source and
+// only augments along paths going one level deeper with every arc, rebuilding the
+// levels once no such path is left. One path per update
+type Dinic struct {
+	flowNetwork
+	level map[*graph.Node]int
+	// arcs of each node in the level graph and how many of them are used up
+	levelArcs map[*graph.Node][]residualArc
+	used      map[*graph.Node]int
+}
+
+func (algo *Dinic) Init() {
+	algo.init()
+}
+
+func (algo *Dinic) GetName() string {
+	return "Dinic"
+}
+
+func (algo *Dinic) Start(g *graph.Graph) error {
+	if err := algo.start(g); err != nil {
+		return err
+	}
+	algo.level = nil
+	return nil
+}
+
+// sort the nodes into levels, false when the sink can not be reached any more
+func (algo *Dinic) buildLevels() bool {
+	algo.level = map[*graph.Node]int{algo.source: 0}
+	algo.levelArcs = make(map[*graph.Node][]residualArc)
+	algo.used = make(map[*graph.Node]int)
+	queue := []*graph.Node{algo.source}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		for _, arc := range algo.arcs(n) {
+			next := arc.to()
+			if _, ok := algo.level[next]; !ok {
+				algo.level[next] = algo.level[n] + 1
+				queue = append(queue, next)
+			}
+			if algo.level[next] == algo.level[n]+1 {
+				algo.levelArcs[n] = append(algo.levelArcs[n], arc)
+			}
+		}
+	}
+	_, reached := algo.level[algo.sink]
+	return reached
+}
+
+// depth first search for a path in the level graph, arcs that lead nowhere are used up
+func (algo *Dinic) findPath() []residualArc {
+	path := make([]residualArc, 0)
+	n := algo.source
+	for n != algo.sink {
+		arcs := algo.levelArcs[n]
+		for algo.used[n] < len(arcs) && algo.residual(arcs[algo.used[n]]) == 0 {
+			algo.used[n]++
+		}
+		if algo.used[n] < len(arcs) {
+			arc := arcs[algo.used[n]]
+			path = append(path, arc)
+			n = arc.to()
+			continue
+		}
+		// dead end, step back and never try this node again in this phase
+		if len(path) == 0 {
+			return nil
+		}
+		last := path[len(path)-1]
+		path = path[:len(path)-1]
+		n = last.from()
+		algo.used[n]++
+	}
+	return path
+}
+
+func (algo *Dinic) Update() (bool, error) {
+	algo.clearPath()
+	for {
+		if algo.level == nil && !algo.buildLevels() {
+			algo.finish()
+			return false, nil
+		}
+		if path := algo.findPath(); path != nil {
+			algo.augment(path)
+			return true, nil
+		}
+		algo.level = nil
+	}
+}
@@ -0,0 +1,252 @@
+package algorithm
+
+import (
+	"fmt"
+	"graphographic/graph"
+	"slices"
+	"strings"
+)
+
+// node on the current trail and the edge it was reached by
+type eulerStep struct {
+	node *graph.Node
+	edge *graph.Edge
+}
+
+// Eulerian path or circuit with Hierholzer's algorithm, a path that uses every edge
+// exactly once. A graph made only of undirected edge pairs is treated as undirected.
+// The trail is extended highlighted until it gets stuck, then it is walked back and
+// the edges are numbered with their position in the final path
+type Euler struct {
+	start      *graph.Node
+	undirected bool
+	links      map[*graph.Node][]undirectedLink
+	// links of each node that are already used
+	next  map[*graph.Node]int
+	used  map[*graph.Edge]bool
+	trail []eulerStep
+	// the final path walked back from its end
+	finished []eulerStep
+	edges    int
+	left     int
+	circuit  bool
+	done     bool
+}
+
+func (algo *Euler) Init() {
+	algo.start = nil
+	algo.done = false
+}
+
+func (algo *Euler) GetName() string {
+	return "Eulerian path"
+}
+
+// the start node is optional, one is picked when nothing was selected
+func (algo *Euler) NodeSelected(node *graph.Node) {
+	algo.start = node
+}
+
+func (algo *Euler) UndoSelect() {
+	algo.start = nil
+}
+
+func (algo *Euler) Start(g *graph.Graph) error {
+	if g.EdgeCount() == 0 {
+		return fmt.Errorf("The graph has no edges")
+	}
+	algo.undirected = g.IsUndirected()
+	algo.links = make(map[*graph.Node][]undirectedLink, g.NodeCount())
+	if algo.undirected {
+		for _, e := range undirectedEdges(g) {
+			algo.links[e.Tail] = append(algo.links[e.Tail], undirectedLink{e, e.Head})
+			algo.links[e.Head] = append(algo.links[e.Head], undirectedLink{e, e.Tail})
+		}
+	} else {
+		for e := range g.Edges() {
+			algo.links[e.Tail] = append(algo.links[e.Tail], undirectedLink{e, e.Head})
+		}
+	}
+	ends, some, err := algo.pathEnds(g)
+	if err != nil {
+		return err
+	}
+	if err := algo.checkConnected(g, some); err != nil {
+		return err
+	}
+	algo.circuit = len(ends) == 0
+	// the picked start stays local so the selection is not changed
+	start := algo.start
+	switch {
+	case algo.start != nil && len(algo.links[algo.start]) == 0:
+		return fmt.Errorf("Node %s has no edges", algo.start.Content)
+	case algo.start != nil && !algo.circuit && !slices.Contains(ends, algo.start):
+		names := make([]string, len(ends))
+		for i, n := range ends {
+			names[i] = n.Content
+		}
+		return fmt.Errorf("An Eulerian path can only start at %s", strings.Join(names, " or "))
+	case algo.start == nil && algo.circuit:
+		start = some
+	case algo.start == nil:
+		start = ends[0]
+	}
+	algo.next = make(map[*graph.Node]int, g.NodeCount())
+	algo.used = make(map[*graph.Edge]bool, g.EdgeCount())
+	algo.edges = 0
+	for _, links := range algo.links {
+		algo.edges += len(links)
+	}
+	if algo.undirected {
+		algo.edges /= 2
+	}
+	algo.left = algo.edges
+	algo.trail = append(algo.trail[:0], eulerStep{node: start})
+	algo.finished = algo.finished[:0]
+	start.Data.Highlighted = true
+	algo.done = false
+	return nil
+}
+
+// Checks the degrees and returns the nodes a path can start at, none when there is a
+// circuit, and some node with edges. The error tells which nodes are the problem
+func (algo *Euler) pathEnds(g *graph.Graph) ([]*graph.Node, *graph.Node, error) {
+	var some *graph.Node
+	if algo.undirected {
+		odd := make([]*graph.Node, 0)
+		for n := range g.Nodes() {
+			// a loop is in the list twice
+			degree := len(algo.links[n])
+			if degree%2 == 1 {
+				odd = append(odd, n)
+			}
+			if degree > 0 && some == nil {
+				some = n
+			}
+		}
+		if len(odd) == 0 || len(odd) == 2 {
+			return odd, some, nil
+		}
+		names := make([]string, len(odd))
+		for i, n := range odd {
+			names[i] = n.Content
+		}
+		return nil, nil, fmt.Errorf("No Eulerian path, %d nodes have an odd degree (%s) but at most 2 may", len(odd), strings.Join(names, ", "))
+	}
+	var first, last *graph.Node
+	for n := range g.Nodes() {
+		out, in := n.OutDegree(), n.InDegree()
+		switch {
+		case out == in:
+		case out == in+1 && first == nil:
+			first = n
+		case out == in+1:
+			return nil, nil, fmt.Errorf("No Eulerian path, %s and %s both have more outgoing than incoming edges", first.Content, n.Content)
+		case in == out+1 && last == nil:
+			last = n
+		case in == out+1:
+			return nil, nil, fmt.Errorf("No Eulerian path, %s and %s both have more incoming than outgoing edges", last.Content, n.Content)
+		default:
+			return nil, nil, fmt.Errorf("No Eulerian path, %s has %d outgoing and %d incoming edges", n.Content, out, in)
+		}
+		if out > 0 && some == nil {
+			some = n
+		}
+	}
+	if first != nil {
+		return []*graph.Node{first}, some, nil
+	}
+	return nil, some, nil
+}
+
+// every edge has to be reachable from first with edge directions ignored
+func (algo *Euler) checkConnected(g *graph.Graph, first *graph.Node) error {
+	seen := map[*graph.Node]bool{first: true}
+	queue := []*graph.Node{first}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		for e := range n.Out() {
+			if !seen[e.Head] {
+				seen[e.Head] = true
+				queue = append(queue, e.Head)
+			}
+		}
+		for e := range n.In() {
+			if !seen[e.Tail] {
+				seen[e.Tail] = true
+				queue = append(queue, e.Tail)
+			}
+		}
+	}
+	for e := range g.Edges() {
+		if !seen[e.Tail] {
+			return fmt.Errorf("No Eulerian path, the edges of %s and %s are in separate parts of the graph", first.Content, e.Tail.Content)
+		}
+	}
+	return nil
+}
+
+func (algo *Euler) mark(e *graph.Edge, explored, highlighted bool) {
+	if algo.undirected {
+		markUndirected(e, explored, highlighted)
+	} else {
+		e.Data.Explored, e.Data.Highlighted = explored, highlighted
+	}
+}
+
+func (algo *Euler) Update() (bool, error) {
+	top := algo.trail[len(algo.trail)-1]
+	links := algo.links[top.node]
+	for algo.next[top.node] < len(links) && algo.used[links[algo.next[top.node]].edge] {
+		algo.next[top.node]++
+	}
+	if i := algo.next[top.node]; i < len(links) {
+		// extend the trail
+		link := links[i]
+		algo.used[link.edge] = true
+		algo.mark(link.edge, false, true)
+		top.node.Data.Highlighted = false
+		link.other.Data.Highlighted = true
+		algo.trail = append(algo.trail, eulerStep{link.other, link.edge})
+		return true, nil
+	}
+	// stuck, the node and the edge it was reached by are final
+	algo.trail = algo.trail[:len(algo.trail)-1]
+	algo.finished = append(algo.finished, top)
+	top.node.Data.Highlighted = false
+	top.node.Data.Explored = true
+	if top.edge == nil {
+		algo.done = true
+		return false, nil
+	}
+	algo.mark(top.edge, true, false)
+	top.edge.Data.Tag = fmt.Sprint(algo.left)
+	if opp := top.edge.Pair(); algo.undirected && opp != nil {
+		opp.Data.Tag = top.edge.Data.Tag
+	}
+	algo.left--
+	algo.trail[len(algo.trail)-1].node.Data.Highlighted = true
+	return true, nil
+}
+
+func (algo *Euler) Result() *Result {
+	if !algo.done {
+		return nil
+	}
+	result := &Result{
+		Summary: fmt.Sprintf("Eulerian path of %d edges", algo.edges),
+		Metrics: []Metric{{"Edges", float64(algo.edges)}},
+	}
+	if algo.circuit {
+		result.Summary = fmt.Sprintf("Eulerian circuit of %d edges", algo.edges)
+	}
+	for i := len(algo.finished) - 1; i >= 0; i-- {
+		result.Nodes = append(result.Nodes, algo.finished[i].node)
+		if i > 0 {
+			// the edge a node was reached by leads on from the node before it
+			result.Edges = append(result.Edges, algo.finished[i-1].edge)
+		}
+	}
+	return result
+}
@@ -0,0 +1,98 @@
+package algorithm
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestEuler(t *testing.T) {
+	for _, test := range []struct {
+		edges  string
+		count  int
+		report string
+	}{
+		// directed circuit through a loop
+		{"a b, b c, c a, a a", 4, "Eulerian circuit of 4 edges"},
+		// directed path, has to start at a
+		{"a b, b c, c a, a d", 4, "Eulerian path of 4 edges"},
+		// opposite edges of equal cost that are no undirected pair
+		{"a b 3, b a 3", 2, "Eulerian circuit of 2 edges"},
+		// undirected house, the path has to run between the two odd nodes
+		{undirected("a b, b c, c d, d a, c e, d e"), 6, "Eulerian path of 6 edges"},
+		{undirected("a b, b c, c a, c d, d e, e c"), 6, "Eulerian circuit of 6 edges"},
+	} {
+		g, _ := buildGraph(t, test.edges)
+		algo := &Euler{}
+		if err := runAlgorithm(t, algo, g); err != nil {
+			t.Fatalf("%q: %v", test.edges, err)
+		}
+		if got := algo.Result().Summary; got != test.report {
+			t.Errorf("%q: report is %q, want %q", test.edges, got, test.report)
+		}
+		// follow the numbered edges, each has to start where the one before ended
+		byTag := make(map[string][][2]string)
+		for e := range g.Edges() {
+			byTag[e.Data.Tag] = append(byTag[e.Data.Tag], [2]string{e.Tail.Content, e.Head.Content})
+		}
+		// nodes the path may be at, the edges of a pair have the same number
+		var at map[string]bool
+		for i := 1; i <= test.count; i++ {
+			next := make(map[string]bool)
+			for _, c := range byTag[fmt.Sprint(i)] {
+				if at == nil || at[c[0]] {
+					next[c[1]] = true
+				}
+			}
+			if len(next) == 0 {
+				t.Fatalf("%q: edge %d does not continue the path", test.edges, i)
+			}
+			at = next
+		}
+	}
+}
+
+func TestEulerErrors(t *testing.T) {
+	for _, test := range []struct {
+		edges string
+		err   string
+	}{
+		{"a b, a c", "No Eulerian path, a has 2 outgoing and 0 incoming edges"},
+		{"a b, c d", "No Eulerian path, a and c both have more outgoing than incoming edges"},
+		{undirected("a b, a c, a d"), "No Eulerian path, 4 nodes have an odd degree (a, b, c, d) but at most 2 may"},
+		{undirected("a b, b c, c a, x y, y z, z x"), "No Eulerian path, the edges of a and x are in separate parts of the graph"},
+		{"x", "The graph has no edges"},
+	} {
+		g, _ := buildGraph(t, test.edges)
+		if err := runAlgorithm(t, &Euler{}, g); err == nil || err.Error() != test.err {
+			t.Errorf("%q: got %v, want %q", test.edges, err, test.err)
+		}
+	}
+}
+
+func TestEulerStartNode(t *testing.T) {
+	g, n := buildGraph(t, "a b, b c, c a, a d")
+	if err := runAlgorithm(t, &Euler{}, g, n["b"]); err == nil || err.Error() != "An Eulerian path can only start at a" {
+		t.Errorf("got %v", err)
+	}
+	g, n = buildGraph(t, "a b, b c, c a")
+	if err := runAlgorithm(t, &Euler{}, g, n["b"]); err != nil {
+		t.Fatal(err)
+	}
+	if g.EdgeBetween(n["b"], n["c"]).Data.Tag != "1" {
+		t.Error("the circuit does not start at the selected node")
+	}
+}
+
+// an automatically picked start is not kept as the selection for the next run
+func TestEulerPickedStartNotSelected(t *testing.T) {
+	algo := &Euler{}
+	algo.Init()
+	g, _ := buildGraph(t, "a b, b c")
+	if err := algo.Start(g); err != nil {
+		t.Fatal(err)
+	}
+	g, _ = buildGraph(t, "x y, y z")
+	if err := algo.Start(g); err != nil {
+		t.Fatalf("the start picked for the first graph was reused: %v", err)
+	}
+}
@@ -0,0 +1,241 @@
+package algorithm
+
+import (
+	"fmt"
+	"graphographic/graph"
+)
+
+// A way through the residual network: along an edge that still has spare capacity
+// or back against an edge that carries flow
+type residualArc struct {
+	edge    *graph.Edge
+	forward bool
+}
+
+func (arc residualArc) from() *graph.Node {
+	if arc.forward {
+		return arc.edge.Tail
+	}
+	return arc.edge.Head
+}
+
+func (arc residualArc) to() *graph.Node {
+	if arc.forward {
+		return arc.edge.Head
+	}
+	return arc.edge.Tail
+}
+
+// State shared by the maximum flow algorithms, edge costs are the capacities.
+// Edges are tagged with flow/capacity
+type flowNetwork struct {
+	graph  *graph.Graph
+	source *graph.Node
+	sink   *graph.Node
+	flow   map[*graph.Edge]int64
+	total  int64
+	// latest augmenting path, highlighted until the next update
+	path []residualArc
+	// edges of the minimum cut once the flow is maximal
+	cut  []*graph.Edge
+	done bool
+}
+
+func (net *flowNetwork) init() {
+	net.source = nil
+	net.sink = nil
+	net.done = false
+}
+
+func (net *flowNetwork) start(g *graph.Graph) error {
+	if net.source == nil || net.sink == nil {
+		net.source = nil
+		net.sink = nil
+		return fmt.Errorf("Source or sink node not selected")
+	}
+	if net.source == net.sink {
+		net.sink = nil
+		return fmt.Errorf("Source and sink must be different nodes")
+	}
+	for e := range g.Edges() {
+		if e.Cost < 0 {
+			return fmt.Errorf("Edge %s -> %s has a negative capacity", e.Tail.Content, e.Head.Content)
+		}
+	}
+	net.graph = g
+	net.flow = make(map[*graph.Edge]int64, g.EdgeCount())
+	for e := range g.Edges() {
+		net.tag(e)
+	}
+	net.total = 0
+	net.path = nil
+	net.cut = nil
+	net.done = false
+	net.source.Data.Highlighted = false
+	net.sink.Data.Highlighted = false
+	return nil
+}
+
+func (net *flowNetwork) tag(e *graph.Edge) {
+	e.Data.Tag = fmt.Sprintf("%d/%d", net.flow[e], e.Cost)
+}
+
+// how much more can go through the arc
+func (net *flowNetwork) residual(arc residualArc) int64 {
+	if arc.forward {
+		return int64(arc.edge.Cost) - net.flow[arc.edge]
+	}
+	return net.flow[arc.edge]
+}
+
+// arcs leaving n in the residual network
+func (net *flowNetwork) arcs(n *graph.Node) []residualArc {
+	arcs := make([]residualArc, 0, n.OutDegree()+n.InDegree())
+	for e := range n.Out() {
+		if arc := (residualArc{e, true}); net.residual(arc) > 0 {
+			arcs = append(arcs, arc)
+		}
+	}
+	for e := range n.In() {
+		if arc := (residualArc{e, false}); net.residual(arc) > 0 {
+			arcs = append(arcs, arc)
+		}
+	}
+	return arcs
+}
+
+func (net *flowNetwork) clearPath() {
+	for _, arc := range net.path {
+		arc.edge.Data.Highlighted = false
+	}
+	net.path = nil
+}
+
+// push the bottleneck amount along path and highlight it
+func (net *flowNetwork) augment(path []residualArc) {
+	bottleneck := net.residual(path[0])
+	for _, arc := range path[1:] {
+		bottleneck = min(bottleneck, net.residual(arc))
+	}
+	for _, arc := range path {
+		if arc.forward {
+			net.flow[arc.edge] += bottleneck
+		} else {
+			net.flow[arc.edge] -= bottleneck
+		}
+		arc.edge.Data.Highlighted = true
+		arc.edge.Data.Explored = net.flow[arc.edge] > 0
+		net.tag(arc.edge)
+	}
+	net.total += bottleneck
+	net.path = path
+}
+
+// Show the minimum cut: nodes the source still reaches in the residual network are
+// group 1, the rest group 2, and the saturated edges between them are highlighted
+func (net *flowNetwork) finish() {
+	reached := map[*graph.Node]bool{net.source: true}
+	queue := []*graph.Node{net.source}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		for _, arc := range net.arcs(n) {
+			if !reached[arc.to()] {
+				reached[arc.to()] = true
+				queue = append(queue, arc.to())
+			}
+		}
+	}
+	for n := range net.graph.Nodes() {
+		if reached[n] {
+			n.Data.Group = 1
+		} else {
+			n.Data.Group = 2
+		}
+	}
+	for e := range net.graph.Edges() {
+		if reached[e.Tail] && !reached[e.Head] {
+			e.Data.Highlighted = true
+			net.cut = append(net.cut, e)
+		}
+	}
+	net.done = true
+}
+
+func (net *flowNetwork) Result() *Result {
+	if !net.done {
+		return nil
+	}
+	return &Result{
+		Summary: fmt.Sprintf("Max flow: %d, min cut of %d edges", net.total, len(net.cut)),
+		Metrics: []Metric{{"Max flow", float64(net.total)}, {"Cut edges", float64(len(net.cut))}},
+		Edges:   net.cut,
+	}
+}
+
+func (net *flowNetwork) NodeSelected(node *graph.Node) {
+	if node == nil {
+		return
+	}
+	if net.source == nil {
+		net.source = node
+		net.source.Data.Highlighted = true
+	} else if net.sink == nil {
+		net.sink = node
+		net.sink.Data.Highlighted = true
+	}
+}
+
+func (net *flowNetwork) UndoSelect() {
+	if net.sink != nil {
+		net.sink.Data.Highlighted = false
+		net.sink = nil
+	} else if net.source != nil {
+		net.source.Data.Highlighted = false
+		net.source = nil
+	}
+}
+
+// Maximum flow that always augments along a shortest path of the residual network,
+// one path per update
+type EdmondsKarp struct {
+	flowNetwork
+}
+
+func (algo *EdmondsKarp) Init() {
+	algo.init()
+}
+
+func (algo *EdmondsKarp) GetName() string {
+	return "Edmonds-Karp"
+}
+
+func (algo *EdmondsKarp) Start(g *graph.Graph) error {
+	return algo.start(g)
+}
+
+func (algo *EdmondsKarp) Update() (bool, error) {
+	algo.clearPath()
+	prev := map[*graph.Node]residualArc{}
+	queue := []*graph.Node{algo.source}
+	for len(queue) > 0 && prev[algo.sink].edge == nil {
+		n := queue[0]
+		queue = queue[1:]
+		for _, arc := range algo.arcs(n) {
+			if next := arc.to(); next != algo.source && prev[next].edge == nil {
+				prev[next] = arc
+				queue = append(queue, next)
+			}
+		}
+	}
+	if prev[algo.sink].edge == nil {
+		algo.finish()
+		return false, nil
+	}
+	path := make([]residualArc, 0)
+	for n := algo.sink; n != algo.source; n = prev[n].from() {
+		path = append(path, prev[n])
+	}
+	algo.augment(path)
+	return true, nil
+}
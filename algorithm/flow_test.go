@@ -0,0 +1,84 @@
+package algorithm
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMaxFlow(t *testing.T) {
+	tests := []struct {
+		name, edges string
+		report      string
+		// nodes on the source side of the cut
+		sourceSide []string
+	}{
+		{
+			"textbook",
+			"s a 16, s c 13, a b 12, c a 4, b c 9, c d 14, d b 7, b t 20, d t 4",
+			"Max flow: 23, min cut of 3 edges",
+			[]string{"s", "a", "c", "d"},
+		},
+		{
+			// the first shortest path has to be partly undone through a backward arc
+			"backward arc",
+			"s a 1, s b 1, a b 1, a t 1, b t 1",
+			"Max flow: 2, min cut of 2 edges",
+			[]string{"s"},
+		},
+		{"unreachable sink", "s a 5, t a 3", "Max flow: 0, min cut of 0 edges", []string{"s", "a"}},
+	}
+	for _, tt := range tests {
+		for _, algo := range []Algorithm{&EdmondsKarp{}, &Dinic{}} {
+			g, n := buildGraph(t, tt.edges)
+			if err := runAlgorithm(t, algo, g, n["s"], n["t"]); err != nil {
+				t.Fatalf("%s on %s: %v", algo.GetName(), tt.name, err)
+			}
+			if got := algo.Result().Summary; got != tt.report {
+				t.Errorf("%s on %s reported %q, want %q", algo.GetName(), tt.name, got, tt.report)
+			}
+			sourceSide := make(map[string]bool)
+			for _, name := range tt.sourceSide {
+				sourceSide[name] = true
+			}
+			for name, node := range n {
+				if want := map[bool]int{true: 1, false: 2}[sourceSide[name]]; node.Data.Group != want {
+					t.Errorf("%s on %s put %s in group %d, want %d", algo.GetName(), tt.name, name, node.Data.Group, want)
+				}
+			}
+			// flow is conserved everywhere but the source and sink
+			for name, node := range n {
+				if name == "s" || name == "t" {
+					continue
+				}
+				balance := 0
+				for e := range node.In() {
+					balance += flowOf(t, e.Data.Tag)
+				}
+				for e := range node.Out() {
+					balance -= flowOf(t, e.Data.Tag)
+				}
+				if balance != 0 {
+					t.Errorf("%s on %s: flow into %s is off by %d", algo.GetName(), tt.name, name, balance)
+				}
+			}
+		}
+	}
+}
+
+func flowOf(t *testing.T, tag string) int {
+	var flow, capacity int
+	if _, err := fmt.Sscanf(tag, "%d/%d", &flow, &capacity); err != nil || flow > capacity {
+		t.Fatalf("edge tag %q is not flow/capacity", tag)
+	}
+	return flow
+}
+
+func TestMaxFlowNeedsSourceAndSink(t *testing.T) {
+	g, n := buildGraph(t, "s t 1")
+	if err := runAlgorithm(t, &Dinic{}, g, n["s"]); err == nil {
+		t.Fatal("expected an error without a sink")
+	}
+	if err := runAlgorithm(t, &Dinic{}, g, n["s"], n["s"]); err == nil {
+		t.Fatal("expected an error when the source is the sink")
+	}
+}
@@ -0,0 +1,182 @@
+package algorithm
+
+import (
+	"fmt"
+	"graphographic/graph"
+)
+
+// All-pairs shortest paths. Every update runs one iteration of the k loop, the
+// intermediate node is highlighted together with the paths that got shorter through it
+type FloydWarshall struct {
+	nodes []*graph.Node
+	// dist[i][j] is the length of the shortest path from nodes[i] to nodes[j] found so
+	// far, next[i][j] the index of the node after nodes[i] on it or -1 without a path
+	dist [][]int64
+	next [][]int
+	// cheapest edge between two nodes
+	direct [][]*graph.Edge
+	k      int
+	done   bool
+}
+
+func (algo *FloydWarshall) Init() {
+	algo.nodes = nil
+	algo.dist = nil
+	algo.next = nil
+	algo.direct = nil
+}
+
+func (algo *FloydWarshall) GetName() string {
+	return "Floyd-Warshall"
+}
+
+// all pairs are computed, nothing has to be selected
+func (algo *FloydWarshall) NodeSelected(node *graph.Node) {}
+func (algo *FloydWarshall) UndoSelect()                   {}
+
+func (algo *FloydWarshall) Start(g *graph.Graph) error {
+	n := g.NodeCount()
+	if n == 0 {
+		return fmt.Errorf("The graph has no nodes")
+	}
+	algo.nodes = make([]*graph.Node, 0, n)
+	index := make(map[*graph.Node]int, n)
+	for node := range g.Nodes() {
+		index[node] = len(algo.nodes)
+		algo.nodes = append(algo.nodes, node)
+	}
+	algo.dist = make([][]int64, n)
+	algo.next = make([][]int, n)
+	algo.direct = make([][]*graph.Edge, n)
+	for i := range n {
+		algo.dist[i] = make([]int64, n)
+		algo.next[i] = make([]int, n)
+		algo.direct[i] = make([]*graph.Edge, n)
+		for j := range n {
+			algo.dist[i][j] = unreachable
+			algo.next[i][j] = -1
+		}
+		algo.dist[i][i] = 0
+		algo.next[i][i] = i
+	}
+	for e := range g.Edges() {
+		i, j := index[e.Tail], index[e.Head]
+		if int64(e.Cost) < algo.dist[i][j] {
+			algo.dist[i][j] = int64(e.Cost)
+			algo.next[i][j] = j
+			algo.direct[i][j] = e
+		}
+	}
+	algo.k = 0
+	algo.done = false
+	return nil
+}
+
+func (algo *FloydWarshall) Update() (bool, error) {
+	for _, node := range algo.nodes {
+		node.Data.Highlighted = false
+		for e := range node.Out() {
+			e.Data.Highlighted = false
+		}
+	}
+	if algo.k >= len(algo.nodes) {
+		algo.done = true
+		return false, nil
+	}
+	k := algo.k
+	algo.k++
+	algo.nodes[k].Data.Highlighted = true
+	algo.nodes[k].Data.Explored = true
+	for i := range algo.nodes {
+		if algo.dist[i][k] == unreachable {
+			continue
+		}
+		for j := range algo.nodes {
+			if algo.dist[k][j] == unreachable || algo.dist[i][k]+algo.dist[k][j] >= algo.dist[i][j] {
+				continue
+			}
+			algo.dist[i][j] = algo.dist[i][k] + algo.dist[k][j]
+			algo.next[i][j] = algo.next[i][k]
+			for _, e := range algo.path(i, j) {
+				e.Data.Highlighted = true
+			}
+		}
+	}
+	for i, node := range algo.nodes {
+		if algo.dist[i][i] < 0 {
+			for _, e := range algo.path(i, k) {
+				e.Data.Highlighted = true
+			}
+			for _, e := range algo.path(k, i) {
+				e.Data.Highlighted = true
+			}
+			return false, fmt.Errorf("Negative cycle through %s", node.Content)
+		}
+	}
+	algo.done = algo.k == len(algo.nodes)
+	return !algo.done, nil
+}
+
+// edges of the shortest path from nodes[i] to nodes[j] known so far
+func (algo *FloydWarshall) path(i, j int) []*graph.Edge {
+	edges := make([]*graph.Edge, 0)
+	// a negative cycle can make the next hops loop, no path is longer than all nodes
+	for steps := 0; i != j && algo.next[i][j] != -1 && steps < len(algo.nodes); steps++ {
+		hop := algo.next[i][j]
+		edges = append(edges, algo.direct[i][hop])
+		i = hop
+	}
+	return edges
+}
+
+// the distances are in the matrix, the result only sums them up
+func (algo *FloydWarshall) Result() *Result {
+	if !algo.done {
+		return nil
+	}
+	pairs, longest := 0, int64(0)
+	for i := range algo.nodes {
+		for j := range algo.nodes {
+			if i != j && algo.dist[i][j] != unreachable {
+				pairs++
+				longest = max(longest, algo.dist[i][j])
+			}
+		}
+	}
+	return &Result{
+		Summary: fmt.Sprintf("Shortest paths between %d pairs of nodes", pairs),
+		Metrics: []Metric{{"Connected pairs", float64(pairs)}, {"Longest distance", float64(longest)}},
+	}
+}
+
+func (algo *FloydWarshall) MatrixCount() int {
+	return 2
+}
+
+func (algo *FloydWarshall) MatrixTitle(table int) string {
+	if table == 0 {
+		return "Distance"
+	}
+	return "Next hop"
+}
+
+func (algo *FloydWarshall) MatrixLabels() []string {
+	labels := make([]string, len(algo.nodes))
+	for i, node := range algo.nodes {
+		labels[i] = node.Content
+	}
+	return labels
+}
+
+func (algo *FloydWarshall) MatrixCell(table, row, col int) string {
+	if table == 0 {
+		if algo.dist[row][col] == unreachable {
+			return "inf"
+		}
+		return fmt.Sprintf("%d", algo.dist[row][col])
+	}
+	if algo.next[row][col] == -1 {
+		return "-"
+	}
+	return algo.nodes[algo.next[row][col]].Content
+}
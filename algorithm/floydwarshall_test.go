@@ -0,0 +1,46 @@
+package algorithm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFloydWarshallMatrix(t *testing.T) {
+	g, _ := buildGraph(t, "a b 3, b c -1, a c 5, c a 2, d")
+	algo := &FloydWarshall{}
+	if err := runAlgorithm(t, algo, g); err != nil {
+		t.Fatal(err)
+	}
+	labels := algo.MatrixLabels()
+	index := make(map[string]int)
+	for i, label := range labels {
+		index[label] = i
+	}
+	tests := []struct {
+		from, to   string
+		dist, next string
+	}{
+		{"a", "c", "2", "b"},
+		{"c", "b", "5", "a"},
+		{"b", "a", "1", "c"},
+		{"a", "a", "0", "a"},
+		{"a", "d", "inf", "-"},
+	}
+	for _, tt := range tests {
+		i, j := index[tt.from], index[tt.to]
+		if got := algo.MatrixCell(0, i, j); got != tt.dist {
+			t.Errorf("distance %s -> %s is %s, want %s", tt.from, tt.to, got, tt.dist)
+		}
+		if got := algo.MatrixCell(1, i, j); got != tt.next {
+			t.Errorf("next hop %s -> %s is %s, want %s", tt.from, tt.to, got, tt.next)
+		}
+	}
+}
+
+func TestFloydWarshallNegativeCycle(t *testing.T) {
+	g, _ := buildGraph(t, "a b 1, b c -3, c a 1")
+	err := runAlgorithm(t, &FloydWarshall{}, g)
+	if err == nil || !strings.Contains(err.Error(), "Negative cycle") {
+		t.Fatalf("expected a negative cycle, got %v", err)
+	}
+}
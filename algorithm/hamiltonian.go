@@ -0,0 +1,214 @@
+package algorithm
+
+import (
+	"fmt"
+	"graphographic/graph"
+)
+
+// the search takes exponential time, larger graphs would never finish
+const hamiltonianMaxNodes = 16
+
+// node on the current path, the edge it was reached by and how many of its
+// outgoing edges were tried
+type hamiltonianStep struct {
+	node  *graph.Node
+	edge  *graph.Edge
+	tried int
+}
+
+// Backtracking search for a path (or cycle) that visits every node exactly once,
+// following edge directions. Every update extends the highlighted path by one node
+// or takes the last one back. A branch is given up early when some node can no
+// longer be reached from the end of the path
+type Hamiltonian struct {
+	Cycle bool
+	start *graph.Node
+	nodes []*graph.Node
+	// nodes the search still has to start from, a cycle can start anywhere
+	roots   []*graph.Node
+	out     map[*graph.Node][]*graph.Edge
+	path    []hamiltonianStep
+	onPath  map[*graph.Node]bool
+	closing *graph.Edge
+	done    bool
+}
+
+func (algo *Hamiltonian) Init() {
+	algo.start = nil
+	algo.done = false
+}
+
+func (algo *Hamiltonian) GetName() string {
+	return "Hamiltonian"
+}
+
+func (algo *Hamiltonian) NextOption() {
+	algo.Cycle = !algo.Cycle
+}
+
+func (algo *Hamiltonian) OptionName() string {
+	if algo.Cycle {
+		return "cycle"
+	}
+	return "path"
+}
+
+// the start node is optional, without one a path is searched from every node
+func (algo *Hamiltonian) NodeSelected(node *graph.Node) {
+	algo.start = node
+}
+
+func (algo *Hamiltonian) UndoSelect() {
+	algo.start = nil
+}
+
+func (algo *Hamiltonian) Start(g *graph.Graph) error {
+	if g.NodeCount() == 0 {
+		return fmt.Errorf("The graph has no nodes")
+	}
+	if g.NodeCount() > hamiltonianMaxNodes {
+		return fmt.Errorf("The Hamiltonian search works on at most %d nodes", hamiltonianMaxNodes)
+	}
+	algo.nodes = algo.nodes[:0]
+	algo.out = make(map[*graph.Node][]*graph.Edge, g.NodeCount())
+	for n := range g.Nodes() {
+		algo.nodes = append(algo.nodes, n)
+		for e := range n.Out() {
+			algo.out[n] = append(algo.out[n], e)
+		}
+	}
+	switch {
+	case algo.start != nil:
+		algo.roots = []*graph.Node{algo.start}
+	case algo.Cycle:
+		algo.roots = algo.nodes[:1]
+	default:
+		algo.roots = algo.nodes
+	}
+	algo.path = algo.path[:0]
+	algo.onPath = make(map[*graph.Node]bool, len(algo.nodes))
+	algo.closing = nil
+	algo.done = false
+	return nil
+}
+
+func (algo *Hamiltonian) push(n *graph.Node, e *graph.Edge) {
+	if len(algo.path) > 0 {
+		algo.path[len(algo.path)-1].node.Data.Highlighted = false
+	}
+	algo.path = append(algo.path, hamiltonianStep{node: n, edge: e})
+	algo.onPath[n] = true
+	n.Data.Explored = true
+	n.Data.Highlighted = true
+	n.Data.Tag = fmt.Sprint(len(algo.path))
+	if e != nil {
+		e.Data.Highlighted = true
+	}
+}
+
+func (algo *Hamiltonian) pop() {
+	last := algo.path[len(algo.path)-1]
+	algo.path = algo.path[:len(algo.path)-1]
+	delete(algo.onPath, last.node)
+	last.node.Data = graph.AlgoData{}
+	if last.edge != nil {
+		last.edge.Data.Highlighted = false
+	}
+	if len(algo.path) > 0 {
+		algo.path[len(algo.path)-1].node.Data.Highlighted = true
+	}
+}
+
+// true when the path can not be completed: a node off the path can not be reached
+// from its end, or for a cycle there is no way back to the first node
+func (algo *Hamiltonian) deadEnd() bool {
+	end := algo.path[len(algo.path)-1].node
+	first := algo.path[0].node
+	seen := map[*graph.Node]bool{end: true}
+	queue := []*graph.Node{end}
+	back := len(algo.path) == len(algo.nodes)
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		for e := range n.Out() {
+			if e.Head == first {
+				back = true
+			}
+			if !algo.onPath[e.Head] && !seen[e.Head] {
+				seen[e.Head] = true
+				queue = append(queue, e.Head)
+			}
+		}
+	}
+	if algo.Cycle && !back {
+		return true
+	}
+	return len(seen)+len(algo.path)-1 < len(algo.nodes)
+}
+
+func (algo *Hamiltonian) Update() (bool, error) {
+	if len(algo.path) == 0 {
+		if len(algo.roots) == 0 {
+			return false, fmt.Errorf("The graph has no Hamiltonian %s", algo.OptionName())
+		}
+		algo.push(algo.roots[0], nil)
+		algo.roots = algo.roots[1:]
+		return true, nil
+	}
+	last := len(algo.path) - 1
+	top := algo.path[last].node
+	if len(algo.path) == len(algo.nodes) {
+		if !algo.Cycle {
+			algo.done = true
+			return false, nil
+		}
+		for e := range top.Out() {
+			if e.Head == algo.path[0].node {
+				algo.closing = e
+				e.Data.Highlighted = true
+				algo.done = true
+				return false, nil
+			}
+		}
+		algo.pop()
+		return true, nil
+	}
+	// the path may grow and shrink again below, the step is kept by its index
+	for algo.path[last].tried < len(algo.out[top]) {
+		e := algo.out[top][algo.path[last].tried]
+		algo.path[last].tried++
+		if algo.onPath[e.Head] {
+			continue
+		}
+		algo.push(e.Head, e)
+		if algo.deadEnd() {
+			algo.pop()
+			continue
+		}
+		return true, nil
+	}
+	algo.pop()
+	return true, nil
+}
+
+func (algo *Hamiltonian) Result() *Result {
+	if !algo.done {
+		return nil
+	}
+	result := &Result{}
+	var cost int64
+	for _, step := range algo.path {
+		result.Nodes = append(result.Nodes, step.node)
+		if step.edge != nil {
+			result.Edges = append(result.Edges, step.edge)
+			cost += int64(step.edge.Cost)
+		}
+	}
+	if algo.closing != nil {
+		result.Edges = append(result.Edges, algo.closing)
+		cost += int64(algo.closing.Cost)
+	}
+	result.Summary = fmt.Sprintf("Hamiltonian %s of cost %d", algo.OptionName(), cost)
+	result.Metrics = []Metric{{"Cost", float64(cost)}}
+	return result
+}
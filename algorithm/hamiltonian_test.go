@@ -0,0 +1,70 @@
+package algorithm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHamiltonian(t *testing.T) {
+	for _, test := range []struct {
+		edges  string
+		cycle  bool
+		report string
+	}{
+		// the cheap chords lead into dead ends the search has to back out of
+		{"a b 1, b c 1, c d 1, d e 1, e a 1, a c 0, b d 0", true, "Hamiltonian cycle of cost 5"},
+		{"a b 1, b c 2, c d 3", false, "Hamiltonian path of cost 6"},
+		// only a path from d works
+		{"a b 1, b c 1, d a 1", false, "Hamiltonian path of cost 3"},
+		{undirected("a b 1, b c 1, c d 1, d a 1"), true, "Hamiltonian cycle of cost 4"},
+	} {
+		g, _ := buildGraph(t, test.edges)
+		algo := &Hamiltonian{Cycle: test.cycle}
+		if err := runAlgorithm(t, algo, g); err != nil {
+			t.Fatalf("%q: %v", test.edges, err)
+		}
+		if got := algo.Result().Summary; got != test.report {
+			t.Errorf("%q: report is %q, want %q", test.edges, got, test.report)
+		}
+		tags := make(map[string]bool)
+		for n := range g.Nodes() {
+			if n.Data.Tag == "" || tags[n.Data.Tag] {
+				t.Errorf("%q: %s has the position %q", test.edges, n.Content, n.Data.Tag)
+			}
+			tags[n.Data.Tag] = true
+		}
+	}
+}
+
+func TestHamiltonianMissing(t *testing.T) {
+	for _, test := range []struct {
+		edges string
+		cycle bool
+	}{
+		{"a b, b c", true},
+		{undirected("a b, a c, a d"), false},
+		{"a b, c", false},
+	} {
+		g, _ := buildGraph(t, test.edges)
+		err := runAlgorithm(t, &Hamiltonian{Cycle: test.cycle}, g)
+		if err == nil || !strings.HasPrefix(err.Error(), "The graph has no Hamiltonian") {
+			t.Errorf("%q: got %v", test.edges, err)
+		}
+		for n := range g.Nodes() {
+			if n.Data.Explored {
+				t.Errorf("%q: %s is still on the path after the search failed", test.edges, n.Content)
+			}
+		}
+	}
+}
+
+func TestHamiltonianGuard(t *testing.T) {
+	names := make([]string, hamiltonianMaxNodes+1)
+	for i := range names {
+		names[i] = string(rune('a' + i))
+	}
+	g, _ := buildGraph(t, strings.Join(names, ","))
+	if err := runAlgorithm(t, &Hamiltonian{}, g); err == nil {
+		t.Fatal("the search started on a graph over the limit")
+	}
+}
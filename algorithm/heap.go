@@ -1,166 +1,164 @@
 package algorithm
 
 import (
+	"cmp"
 	"fmt"
-
-	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
-// import "fmt"
+// Binary min heap that tracks the position of every value, which makes looking a
+// value up O(1) and DecreaseKey O(log n). Values must be unique within the heap
+type IndexedHeap[K cmp.Ordered, V comparable] struct {
+	keys []K
+	vals []V
+	// index of each value in keys and vals
+	pos map[V]int
+}
 
-type elem[T any] struct {
-	key int32
-	val T
+func NewIndexedHeap[K cmp.Ordered, V comparable]() *IndexedHeap[K, V] {
+	return &IndexedHeap[K, V]{
+		pos: make(map[V]int),
+	}
 }
 
-type MinHeap[T any] = []elem[T]
+func (heap *IndexedHeap[K, V]) Len() int {
+	return len(heap.vals)
+}
 
-func Len[T any](heap *MinHeap[T]) int {
-	return len(*heap)
+func (heap *IndexedHeap[K, V]) Contains(v V) bool {
+	_, ok := heap.pos[v]
+	return ok
 }
 
-func GetMin[T any](heap *MinHeap[T]) (int32, *T) {
-	var ret *T = nil
-	if Len(heap) > 0 {
-		ret = &(*heap)[0].val
+// Get the key of v, false when v is not in the heap
+func (heap *IndexedHeap[K, V]) Key(v V) (K, bool) {
+	i, ok := heap.pos[v]
+	if !ok {
+		var zero K
+		return zero, false
 	}
-	return (*heap)[0].key, ret
+	return heap.keys[i], true
 }
 
-func Pop[T any](heap *MinHeap[T]) {
-	if Len(heap) > 0 {
-		swap(heap, 0, Len(heap) - 1)
-		*heap = (*heap)[:Len(heap) - 1]
+// Add v with key k, a value already in the heap gets its key replaced
+func (heap *IndexedHeap[K, V]) Push(k K, v V) {
+	if i, ok := heap.pos[v]; ok {
+		heap.keys[i] = k
+		heap.fix(i)
+		return
 	}
-	siftDown(heap, 0)
+	heap.keys = append(heap.keys, k)
+	heap.vals = append(heap.vals, v)
+	heap.pos[v] = len(heap.vals) - 1
+	heap.siftUp(len(heap.vals) - 1)
+	heap.verify()
 }
 
-func Insert[T any](heap *MinHeap[T], k int32, v T) {
-	*heap = append(*heap, elem[T]{ key: k, val: v })
-	siftUp(heap, Len(heap) - 1)
-	verifyHeapProp(heap)
+// Get the smallest key and its value without removing them, the heap must not be empty
+func (heap *IndexedHeap[K, V]) Min() (K, V) {
+	return heap.keys[0], heap.vals[0]
 }
 
-func Search[T comparable](heap *MinHeap[T], v T) (int32, bool) {
-	found := -1
-	for i, elem := range *heap {
-		if elem.val == v {
-			found = i
-			break
-		}
-	}
-	if found != -1 {
-		return (*heap)[found].key, true
-	}
-	return int32(0), false
+// Remove and return the smallest key and its value, the heap must not be empty
+func (heap *IndexedHeap[K, V]) Pop() (K, V) {
+	k, v := heap.keys[0], heap.vals[0]
+	heap.removeAt(0)
+	return k, v
 }
 
-func Delete[T comparable](heap *MinHeap[T], v T) {
-	found := -1
-	for i, elem := range *heap {
-		if elem.val == v {
-			found = i
-			break
-		}
-	}
-	if found != -1 {
-		//swap found with last element
-		swap(heap, found, Len(heap) - 1)
-		// remove found from heap
-		*heap = (*heap)[:Len(heap)-1]
-		// sift swapped element up or down to maintain heap property
-		if found != Len(heap) {
-			found = siftUp(heap, found)
-			siftDown(heap, found)
-		}
+// Lower the key of v to k. Nothing happens and false is returned when v is not
+// in the heap or k is not smaller than its key
+func (heap *IndexedHeap[K, V]) DecreaseKey(v V, k K) bool {
+	i, ok := heap.pos[v]
+	if !ok || k >= heap.keys[i] {
+		return false
 	}
-	verifyHeapProp(heap)
+	heap.keys[i] = k
+	heap.siftUp(i)
+	heap.verify()
+	return true
 }
 
-func parent(idx int) int {
-	if idx == 0 {
-		return -1
-	}
-	return (idx - 1) / 2
-}
-func leftChild[T any](heap *MinHeap[T], idx int) int {
-	ret := idx*2 + 1
-	if ret >= len(*heap) {
-		return -1
-	} else {
-		return ret
+// Remove v from the heap, false when it was not there
+func (heap *IndexedHeap[K, V]) Remove(v V) bool {
+	i, ok := heap.pos[v]
+	if !ok {
+		return false
 	}
+	heap.removeAt(i)
+	return true
 }
-func rightChild[T any](heap *MinHeap[T], idx int) int {
-	ret := idx*2 + 2
-	if ret >= len(*heap) {
-		return -1
-	} else {
-		return ret
+
+func (heap *IndexedHeap[K, V]) removeAt(i int) {
+	last := len(heap.vals) - 1
+	heap.swap(i, last)
+	delete(heap.pos, heap.vals[last])
+	heap.keys = heap.keys[:last]
+	heap.vals = heap.vals[:last]
+	if i < last {
+		heap.fix(i)
 	}
+	heap.verify()
 }
 
-func siftDown[T any](heap *MinHeap[T], elem int) int {
-	if elem == -1 {
-		return -1
+// restore the heap property after the key at i changed either way
+func (heap *IndexedHeap[K, V]) fix(i int) {
+	if heap.siftUp(i) == i {
+		heap.siftDown(i)
 	}
+}
+
+func (heap *IndexedHeap[K, V]) siftDown(i int) int {
 	for {
-		left, right := leftChild(heap, elem), rightChild(heap, elem)
-		var candidate int = -1
-		// if elem has a left child, it could have a right one, but without a left child there is no right child
-		candidate = left
-		if right != -1 && (*heap)[right].key <= (*heap)[left].key {
+		left, right := 2*i+1, 2*i+2
+		if left >= len(heap.keys) {
+			return i
+		}
+		// without a left child there is no right child
+		candidate := left
+		if right < len(heap.keys) && heap.keys[right] < heap.keys[left] {
 			candidate = right
 		}
-		if candidate != -1 && (*heap)[candidate].key < (*heap)[elem].key {
-			swap(heap, elem, candidate)
-			elem = candidate
-		} else {
-			return elem
+		if heap.keys[candidate] >= heap.keys[i] {
+			return i
 		}
+		heap.swap(i, candidate)
+		i = candidate
 	}
 }
 
-func siftUp[T any](heap *MinHeap[T], elem int) int {
-	if elem == -1 {
-		return -1
-	}
-	for {
-		parent := parent(elem)
-		if parent != -1 && (*heap)[parent].key > (*heap)[elem].key {
-			swap(heap, parent, elem)
-			elem = parent
-		} else {
-			return elem
+func (heap *IndexedHeap[K, V]) siftUp(i int) int {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if heap.keys[parent] <= heap.keys[i] {
+			break
 		}
+		heap.swap(parent, i)
+		i = parent
 	}
+	return i
 }
 
-func verifyHeapProp[T any](heap* MinHeap[T]){
-	for i, e := range *heap {
-		p := parent(i)
-		l, r := leftChild(heap, i), rightChild(heap, i)
-		if p != -1 && (*heap)[p].key > e.key {
-			rl.TraceLog(rl.LogError, "PARENT HEAP PROPERTY VIOLATED IDX: %d", i)
-			printKeys(heap)
-		}
-		if l != -1 && (*heap)[l].key < e.key {
-			rl.TraceLog(rl.LogError, "LEFT HEAP PROPERTY VIOLATED IDX: %d", i)
-			printKeys(heap)
-		}
-		if r != -1 && (*heap)[r].key < e.key {
-			rl.TraceLog(rl.LogError, "RIGHT HEAP PROPERTY VIOLATED IDX: %d", i)
-			printKeys(heap)
-		}
-	}
+func (heap *IndexedHeap[K, V]) swap(a, b int) {
+	heap.keys[a], heap.keys[b] = heap.keys[b], heap.keys[a]
+	heap.vals[a], heap.vals[b] = heap.vals[b], heap.vals[a]
+	heap.pos[heap.vals[a]] = a
+	heap.pos[heap.vals[b]] = b
 }
 
-func swap[T any](heap* MinHeap[T], a, b int){
-	(*heap)[a], (*heap)[b] = (*heap)[b], (*heap)[a]
-}
-func printKeys[T any](heap* MinHeap[T]){
-	for _, e := range *heap {
-		fmt.Print(e.key, " ")
+// walks the whole heap, so it only runs in builds with the heapdebug tag
+func (heap *IndexedHeap[K, V]) verify() {
+	if !heapDebug {
+		return
+	}
+	if len(heap.pos) != len(heap.vals) {
+		panic(fmt.Sprintf("heap has %d values but %d positions", len(heap.vals), len(heap.pos)))
+	}
+	for i := range heap.vals {
+		if heap.pos[heap.vals[i]] != i {
+			panic(fmt.Sprintf("heap position of index %d is %d", i, heap.pos[heap.vals[i]]))
+		}
+		if parent := (i - 1) / 2; i > 0 && heap.keys[parent] > heap.keys[i] {
+			panic(fmt.Sprintf("heap property violated at index %d: %v", i, heap.keys))
+		}
 	}
-	fmt.Println()
 }
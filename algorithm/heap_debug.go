@@ -0,0 +1,6 @@
+//go:build heapdebug
+
+package algorithm
+
+// check the heap invariants after every change
+const heapDebug = true
@@ -0,0 +1,5 @@
+//go:build !heapdebug
+
+package algorithm
+
+const heapDebug = false
@@ -0,0 +1,106 @@
+package algorithm
+
+import (
+	"graphographic/graph"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestIndexedHeapOrder(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	heap := NewIndexedHeap[int32, int]()
+	keys := make(map[int]int32)
+	for v := range 500 {
+		keys[v] = rng.Int31n(1000)
+		heap.Push(keys[v], v)
+	}
+	// lower some keys and drop a few values entirely
+	for v := 0; v < 500; v += 3 {
+		keys[v] -= 1 + rng.Int31n(500)
+		if !heap.DecreaseKey(v, keys[v]) {
+			t.Fatalf("DecreaseKey(%d, %d) did not lower the key", v, keys[v])
+		}
+	}
+	for v := 1; v < 500; v += 7 {
+		if !heap.Remove(v) {
+			t.Fatalf("Remove(%d) did not find the value", v)
+		}
+		delete(keys, v)
+	}
+	want := make([]int32, 0, len(keys))
+	for _, k := range keys {
+		want = append(want, k)
+	}
+	slices.Sort(want)
+	got := make([]int32, 0, len(keys))
+	for heap.Len() > 0 {
+		k, v := heap.Pop()
+		if keys[v] != k {
+			t.Fatalf("value %d came out with key %d, want %d", v, k, keys[v])
+		}
+		if heap.Contains(v) {
+			t.Fatalf("value %d is still in the heap after Pop", v)
+		}
+		got = append(got, k)
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("keys came out as %v, want %v", got, want)
+	}
+}
+
+func TestDecreaseKeyIgnoresLargerKeys(t *testing.T) {
+	heap := NewIndexedHeap[int32, string]()
+	heap.Push(5, "a")
+	if heap.DecreaseKey("a", 7) || heap.DecreaseKey("b", 1) {
+		t.Fatal("DecreaseKey reported a change that should not happen")
+	}
+	if k, _ := heap.Key("a"); k != 5 {
+		t.Fatalf("key of a is %d, want 5", k)
+	}
+}
+
+func BenchmarkIndexedHeap(b *testing.B) {
+	const n = 100_000
+	rng := rand.New(rand.NewSource(1))
+	for b.Loop() {
+		heap := NewIndexedHeap[int32, int]()
+		for v := range n {
+			heap.Push(rng.Int31(), v)
+		}
+		for v := range n {
+			if k, ok := heap.Key(v); ok {
+				heap.DecreaseKey(v, k/2)
+			}
+		}
+		for heap.Len() > 0 {
+			heap.Pop()
+		}
+	}
+}
+
+// runs Dijkstra to completion on a random graph with 100k edges
+func BenchmarkDijkstra(b *testing.B) {
+	const nodeCount, edgeCount = 10_000, 100_000
+	rng := rand.New(rand.NewSource(1))
+	g := graph.New()
+	nodes := make([]*graph.Node, nodeCount)
+	for i := range nodes {
+		nodes[i] = g.AddNode(graph.NewNode())
+	}
+	for g.EdgeCount() < edgeCount {
+		g.AddEdge(nodes[rng.Intn(nodeCount)], nodes[rng.Intn(nodeCount)]).Cost = rng.Int31n(100)
+	}
+	for b.Loop() {
+		algo := &Dijkstra{}
+		algo.Init()
+		algo.NodeSelected(nodes[0])
+		algo.NodeSelected(nodes[nodeCount-1])
+		if err := algo.Start(&g); err != nil {
+			b.Fatal(err)
+		}
+		for running := true; running; {
+			running, _ = algo.Update()
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package algorithm
+
+import (
+	"fmt"
+	"graphographic/graph"
+	"math"
+)
+
+// Maximum matching of a bipartite graph, edge directions are ignored. The graph is
+// split into two groups first, then every update finds one shortest augmenting path,
+// highlights it and flips which of its edges are matched. Matched edges are explored
+type HopcroftKarp struct {
+	nodes []*graph.Node
+	links map[*graph.Node][]undirectedLink
+	// free nodes of the first group, where the search for augmenting paths begins
+	left []*graph.Node
+	mate map[*graph.Node]undirectedLink
+	// layers of the current phase, the distance of left nodes from a free left node
+	dist map[*graph.Node]int
+	// how many links of a left node were tried in the current phase
+	tried   map[*graph.Node]int
+	path    []*graph.Edge
+	matched int
+	done    bool
+}
+
+func (algo *HopcroftKarp) Init() {
+	algo.done = false
+}
+
+func (algo *HopcroftKarp) GetName() string {
+	return "Hopcroft-Karp"
+}
+
+// the whole graph is matched, nothing has to be selected
+func (algo *HopcroftKarp) NodeSelected(node *graph.Node) {}
+func (algo *HopcroftKarp) UndoSelect()                   {}
+
+func (algo *HopcroftKarp) Start(g *graph.Graph) error {
+	split := &Bipartite{}
+	if err := split.Start(g); err != nil {
+		return err
+	}
+	for running := true; running; {
+		var err error
+		if running, err = split.Update(); err != nil {
+			return err
+		}
+	}
+	algo.nodes = split.nodes
+	algo.links = split.links
+	algo.left = algo.left[:0]
+	for _, n := range algo.nodes {
+		n.Data.Explored = false
+		if n.Data.Group == 1 {
+			algo.left = append(algo.left, n)
+		}
+	}
+	for e := range g.Edges() {
+		e.Data.Explored = false
+	}
+	algo.mate = make(map[*graph.Node]undirectedLink, len(algo.nodes))
+	algo.dist = nil
+	algo.path = nil
+	algo.matched = 0
+	algo.done = false
+	return nil
+}
+
+func (algo *HopcroftKarp) free(n *graph.Node) bool {
+	return algo.mate[n].edge == nil
+}
+
+// layer the left nodes by breadth first search from the free ones, false when no
+// augmenting path is left
+func (algo *HopcroftKarp) buildLayers() bool {
+	algo.dist = make(map[*graph.Node]int, len(algo.left))
+	algo.tried = make(map[*graph.Node]int, len(algo.left))
+	queue := make([]*graph.Node, 0)
+	for _, u := range algo.left {
+		if algo.free(u) {
+			algo.dist[u] = 0
+			queue = append(queue, u)
+		}
+	}
+	found := false
+	for len(queue) > 0 {
+		u := queue[0]
+		queue = queue[1:]
+		for _, link := range algo.links[u] {
+			v := link.other
+			if algo.free(v) {
+				found = true
+			} else if w := algo.mate[v].other; !algo.layered(w) {
+				algo.dist[w] = algo.dist[u] + 1
+				queue = append(queue, w)
+			}
+		}
+	}
+	return found
+}
+
+func (algo *HopcroftKarp) layered(n *graph.Node) bool {
+	_, ok := algo.dist[n]
+	return ok
+}
+
+// depth first search along the layers for a free right node, the links of the path
+// from u are returned in order
+func (algo *HopcroftKarp) augmentingPath(u *graph.Node) []undirectedLink {
+	for ; algo.tried[u] < len(algo.links[u]); algo.tried[u]++ {
+		link := algo.links[u][algo.tried[u]]
+		v := link.other
+		if algo.free(v) {
+			return []undirectedLink{link}
+		}
+		if w := algo.mate[v].other; algo.layered(w) && algo.dist[w] == algo.dist[u]+1 {
+			if rest := algo.augmentingPath(w); rest != nil {
+				return append([]undirectedLink{link, algo.mate[v]}, rest...)
+			}
+		}
+	}
+	// a dead end for the rest of the phase
+	algo.dist[u] = math.MaxInt
+	return nil
+}
+
+func (algo *HopcroftKarp) Update() (bool, error) {
+	for _, e := range algo.path {
+		markUndirected(e, e.Data.Explored, false)
+	}
+	algo.path = nil
+	for {
+		if algo.dist == nil && !algo.buildLayers() {
+			algo.done = true
+			return false, nil
+		}
+		for _, u := range algo.left {
+			if !algo.free(u) || algo.dist[u] != 0 {
+				continue
+			}
+			if path := algo.augmentingPath(u); path != nil {
+				algo.flip(u, path)
+				return true, nil
+			}
+		}
+		algo.dist = nil
+	}
+}
+
+// the unmatched links of the path become matched and the matched ones unmatched
+func (algo *HopcroftKarp) flip(u *graph.Node, path []undirectedLink) {
+	for i, link := range path {
+		algo.path = append(algo.path, link.edge)
+		if i%2 == 1 {
+			markUndirected(link.edge, false, true)
+			continue
+		}
+		v := link.other
+		algo.mate[u] = link
+		algo.mate[v] = undirectedLink{link.edge, u}
+		markUndirected(link.edge, true, true)
+		if i+1 < len(path) {
+			u = path[i+1].other
+		}
+	}
+	algo.matched++
+}
+
+func (algo *HopcroftKarp) Result() *Result {
+	if !algo.done {
+		return nil
+	}
+	result := &Result{
+		Summary: fmt.Sprintf("Maximum matching: %d pairs", algo.matched),
+		Metrics: []Metric{{"Pairs", float64(algo.matched)}},
+	}
+	for _, u := range algo.left {
+		if !algo.free(u) {
+			result.Edges = append(result.Edges, algo.mate[u].edge)
+		}
+	}
+	return result
+}
@@ -0,0 +1,92 @@
+package algorithm
+
+import (
+	"cmp"
+	"fmt"
+	"graphographic/graph"
+	"slices"
+)
+
+// Minimum spanning tree built from the cheapest edges that do not close a cycle.
+// One update highlights the next edge, the following one accepts it into the tree
+// or rejects it
+type Kruskal struct {
+	edges     []*graph.Edge
+	index     map[*graph.Node]int
+	sets      *unionFind
+	next      int
+	candidate *graph.Edge
+	weight    int64
+	tree      []*graph.Edge
+	trees     int
+	done      bool
+}
+
+func (algo *Kruskal) Init() {
+	algo.edges = nil
+	algo.done = false
+}
+
+func (algo *Kruskal) GetName() string {
+	return "Kruskal"
+}
+
+// the whole graph is spanned, nothing has to be selected
+func (algo *Kruskal) NodeSelected(node *graph.Node) {}
+func (algo *Kruskal) UndoSelect()                   {}
+
+func (algo *Kruskal) Start(g *graph.Graph) error {
+	if g.NodeCount() == 0 {
+		return fmt.Errorf("The graph has no nodes")
+	}
+	algo.edges = undirectedEdges(g)
+	slices.SortStableFunc(algo.edges, func(a, b *graph.Edge) int {
+		return cmp.Compare(a.Cost, b.Cost)
+	})
+	algo.index = make(map[*graph.Node]int, g.NodeCount())
+	for n := range g.Nodes() {
+		algo.index[n] = len(algo.index)
+	}
+	algo.sets = newUnionFind(g.NodeCount())
+	algo.next = 0
+	algo.candidate = nil
+	algo.weight = 0
+	algo.tree = algo.tree[:0]
+	algo.trees = g.NodeCount()
+	algo.done = false
+	return nil
+}
+
+func (algo *Kruskal) Update() (bool, error) {
+	if algo.candidate != nil {
+		e := algo.candidate
+		algo.candidate = nil
+		if algo.sets.union(algo.index[e.Tail], algo.index[e.Head]) {
+			markUndirected(e, true, false)
+			e.Tail.Data.Explored = true
+			e.Head.Data.Explored = true
+			algo.weight += int64(e.Cost)
+			algo.tree = append(algo.tree, e)
+			algo.trees--
+		} else {
+			markUndirected(e, false, false)
+		}
+		return true, nil
+	}
+	// a tree over all nodes is complete, the remaining edges would all be rejected
+	if algo.next == len(algo.edges) || algo.trees == 1 {
+		algo.done = true
+		return false, nil
+	}
+	algo.candidate = algo.edges[algo.next]
+	algo.next++
+	markUndirected(algo.candidate, false, true)
+	return true, nil
+}
+
+func (algo *Kruskal) Result() *Result {
+	if !algo.done {
+		return nil
+	}
+	return spanningTreeResult(algo.weight, algo.trees, algo.tree)
+}
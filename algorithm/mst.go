@@ -0,0 +1,60 @@
+package algorithm
+
+import (
+	"fmt"
+	"graphographic/graph"
+)
+
+func spanningTreeResult(weight int64, trees int, tree []*graph.Edge) *Result {
+	result := &Result{
+		Summary: fmt.Sprintf("Total weight: %d", weight),
+		Metrics: []Metric{{"Weight", float64(weight)}, {"Trees", float64(trees)}},
+		Edges:   tree,
+	}
+	if trees > 1 {
+		result.Summary = fmt.Sprintf("Total weight: %d (forest of %d trees)", weight, trees)
+	}
+	return result
+}
+
+// Disjoint sets of the numbers 0 to n-1
+type unionFind struct {
+	parent []int
+	rank   []int
+}
+
+func newUnionFind(n int) *unionFind {
+	uf := &unionFind{
+		parent: make([]int, n),
+		rank:   make([]int, n),
+	}
+	for i := range uf.parent {
+		uf.parent[i] = i
+	}
+	return uf
+}
+
+func (uf *unionFind) find(x int) int {
+	for uf.parent[x] != x {
+		// path halving
+		uf.parent[x] = uf.parent[uf.parent[x]]
+		x = uf.parent[x]
+	}
+	return x
+}
+
+// merge the sets of a and b, false when they already were one set
+func (uf *unionFind) union(a, b int) bool {
+	a, b = uf.find(a), uf.find(b)
+	if a == b {
+		return false
+	}
+	if uf.rank[a] < uf.rank[b] {
+		a, b = b, a
+	}
+	uf.parent[b] = a
+	if uf.rank[a] == uf.rank[b] {
+		uf.rank[a]++
+	}
+	return true
+}
@@ -0,0 +1,67 @@
+package algorithm
+
+import (
+	"strings"
+	"testing"
+)
+
+// every edge as an undirected pair, like the UNDIRECTED connect mode makes them
+func undirected(edges string) string {
+	specs := strings.Split(edges, ",")
+	for i, spec := range specs {
+		if f := strings.Fields(spec); len(f) >= 2 {
+			specs[i] = strings.Join(append([]string{f[0], "-"}, f[1:]...), " ")
+		}
+	}
+	return strings.Join(specs, ",")
+}
+
+func TestSpanningTrees(t *testing.T) {
+	tests := []struct {
+		name   string
+		edges  string
+		report string
+		// edges of the tree, each counted once
+		treeEdges int
+	}{
+		{"square with diagonal", undirected("a b 1, b c 2, c d 1, d a 3, a c 1"), "Total weight: 3", 3},
+		{"directed edges count too", "a b 4, c b 1, a c 2", "Total weight: 3", 2},
+		{"forest", undirected("a b 2, c d 5, d e 1, c e 7") + ", f", "Total weight: 8 (forest of 3 trees)", 3},
+	}
+	for _, tt := range tests {
+		for _, algo := range []Algorithm{&Prim{}, &Kruskal{}} {
+			g, _ := buildGraph(t, tt.edges)
+			if err := runAlgorithm(t, algo, g); err != nil {
+				t.Fatalf("%s on %s: %v", algo.GetName(), tt.name, err)
+			}
+			if got := algo.Result().Summary; got != tt.report {
+				t.Errorf("%s on %s reported %q, want %q", algo.GetName(), tt.name, got, tt.report)
+			}
+			explored := 0
+			for _, e := range undirectedEdges(g) {
+				if e.Data.Explored {
+					explored++
+				}
+				if e.Data.Highlighted {
+					t.Errorf("%s on %s left a candidate highlighted", algo.GetName(), tt.name)
+				}
+			}
+			if explored != tt.treeEdges {
+				t.Errorf("%s on %s accepted %d edges, want %d", algo.GetName(), tt.name, explored, tt.treeEdges)
+			}
+		}
+	}
+}
+
+func TestUnionFind(t *testing.T) {
+	uf := newUnionFind(5)
+	if !uf.union(0, 1) || !uf.union(3, 4) || !uf.union(1, 4) {
+		t.Fatal("union of separate sets failed")
+	}
+	if uf.union(0, 3) {
+		t.Fatal("0 and 3 are already in one set")
+	}
+	if uf.find(2) == uf.find(0) {
+		t.Fatal("2 was never merged")
+	}
+}
@@ -0,0 +1,129 @@
+package algorithm
+
+import (
+	"fmt"
+	"graphographic/graph"
+)
+
+// Minimum spanning tree grown from one node by always taking the cheapest edge
+// leaving the tree. Starts from the selected node or from any node when none is
+// selected, a graph in several pieces gets a tree for each of them. One update
+// highlights the next edge, the following one accepts it into the tree or rejects it
+type Prim struct {
+	start *graph.Node
+	nodes []*graph.Node
+	// edges leaving the tree keyed by cost, lazily dropped once both ends are in the tree
+	frontier  *IndexedHeap[int32, *graph.Edge]
+	inTree    map[*graph.Node]bool
+	candidate *graph.Edge
+	weight    int64
+	tree      []*graph.Edge
+	trees     int
+	done      bool
+}
+
+func (algo *Prim) Init() {
+	algo.start = nil
+	algo.done = false
+}
+
+func (algo *Prim) GetName() string {
+	return "Prim"
+}
+
+func (algo *Prim) Start(g *graph.Graph) error {
+	if g.NodeCount() == 0 {
+		return fmt.Errorf("The graph has no nodes")
+	}
+	algo.nodes = algo.nodes[:0]
+	for n := range g.Nodes() {
+		algo.nodes = append(algo.nodes, n)
+	}
+	algo.frontier = NewIndexedHeap[int32, *graph.Edge]()
+	algo.inTree = make(map[*graph.Node]bool, g.NodeCount())
+	algo.candidate = nil
+	algo.weight = 0
+	algo.tree = algo.tree[:0]
+	algo.trees = 0
+	algo.done = false
+	if algo.start != nil {
+		algo.start.Data.Highlighted = false
+		algo.trees++
+		algo.grow(algo.start)
+	}
+	return nil
+}
+
+// add n to the tree and the edges it has to nodes outside of it to the frontier
+func (algo *Prim) grow(n *graph.Node) {
+	algo.inTree[n] = true
+	n.Data.Explored = true
+	for e := range n.Out() {
+		if !algo.inTree[e.Head] {
+			algo.frontier.Push(e.Cost, e)
+		}
+	}
+	// direction is ignored, an edge into the tree can be taken the other way
+	for e := range n.In() {
+		if !algo.inTree[e.Tail] && e.Pair() == nil {
+			algo.frontier.Push(e.Cost, e)
+		}
+	}
+}
+
+func (algo *Prim) Update() (bool, error) {
+	if algo.candidate != nil {
+		e := algo.candidate
+		algo.candidate = nil
+		if !algo.inTree[e.Tail] || !algo.inTree[e.Head] {
+			markUndirected(e, true, false)
+			algo.weight += int64(e.Cost)
+			algo.tree = append(algo.tree, e)
+			if algo.inTree[e.Tail] {
+				algo.grow(e.Head)
+			} else {
+				algo.grow(e.Tail)
+			}
+		} else {
+			markUndirected(e, false, false)
+		}
+		return true, nil
+	}
+	if algo.frontier.Len() > 0 {
+		_, algo.candidate = algo.frontier.Pop()
+		markUndirected(algo.candidate, false, true)
+		return true, nil
+	}
+	// the tree can not grow any more, start the next one
+	for _, n := range algo.nodes {
+		if !algo.inTree[n] {
+			algo.trees++
+			algo.grow(n)
+			return true, nil
+		}
+	}
+	algo.done = true
+	return false, nil
+}
+
+func (algo *Prim) Result() *Result {
+	if !algo.done {
+		return nil
+	}
+	return spanningTreeResult(algo.weight, algo.trees, algo.tree)
+}
+
+func (algo *Prim) NodeSelected(node *graph.Node) {
+	if algo.start != nil {
+		algo.start.Data.Highlighted = false
+	}
+	if algo.start = node; algo.start != nil {
+		algo.start.Data.Highlighted = true
+	}
+}
+
+func (algo *Prim) UndoSelect() {
+	if algo.start != nil {
+		algo.start.Data.Highlighted = false
+	}
+}
@@ -0,0 +1,81 @@
+package algorithm
+
+import (
+	"fmt"
+	"graphographic/graph"
+	"strconv"
+	"strings"
+)
+
+// Outcome of a finished run, shown in a panel next to the graph
+type Result struct {
+	// one line description, like the cost of a path and the nodes along it
+	Summary string
+	// numbers describing the outcome, in the order they are shown
+	Metrics []Metric
+	// what the run picked out, in order where the order means something, like a path
+	Nodes []*graph.Node
+	Edges []*graph.Edge
+}
+
+type Metric struct {
+	Name  string
+	Value float64
+}
+
+func (m Metric) String() string {
+	if m.Value == float64(int64(m.Value)) {
+		return m.Name + ": " + strconv.FormatInt(int64(m.Value), 10)
+	}
+	return fmt.Sprintf("%s: %.2f", m.Name, m.Value)
+}
+
+// Nodes of the result by their content
+func (r *Result) NodeList() string {
+	names := make([]string, len(r.Nodes))
+	for i, n := range r.Nodes {
+		names[i] = n.Content
+	}
+	return strings.Join(names, ", ")
+}
+
+// Edges of the result as "tail -> head"
+func (r *Result) EdgeList() string {
+	edges := make([]string, len(r.Edges))
+	for i, e := range r.Edges {
+		edges[i] = e.Tail.Content + " -> " + e.Head.Content
+	}
+	return strings.Join(edges, ", ")
+}
+
+// The whole result as text, a line for the summary, each metric and the lists
+func (r *Result) String() string {
+	lines := []string{r.Summary}
+	for _, m := range r.Metrics {
+		lines = append(lines, m.String())
+	}
+	if len(r.Nodes) > 0 {
+		lines = append(lines, "Nodes: "+r.NodeList())
+	}
+	if len(r.Edges) > 0 {
+		lines = append(lines, "Edges: "+r.EdgeList())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Result of a path search, edges go from start to the end of the path in order
+func pathResult(start *graph.Node, edges []*graph.Edge) *Result {
+	result := &Result{Nodes: []*graph.Node{start}, Edges: edges}
+	var cost int64
+	for _, e := range edges {
+		result.Nodes = append(result.Nodes, e.Head)
+		cost += int64(e.Cost)
+	}
+	names := make([]string, len(result.Nodes))
+	for i, n := range result.Nodes {
+		names[i] = n.Content
+	}
+	result.Summary = fmt.Sprintf("Path cost %d: %s", cost, strings.Join(names, " -> "))
+	result.Metrics = []Metric{{"Cost", float64(cost)}, {"Edges", float64(len(edges))}}
+	return result
+}
@@ -0,0 +1,87 @@
+package algorithm
+
+import (
+	"testing"
+)
+
+func TestPathResults(t *testing.T) {
+	for _, algo := range []Algorithm{&Dijkstra{}, &AStar{}} {
+		g, n := buildGraph(t, "a b 1, b c 1, a c 5, c d 2, x")
+		if err := runAlgorithm(t, algo, g, n["a"], n["d"]); err != nil {
+			t.Fatal(err)
+		}
+		result := algo.Result()
+		if result == nil {
+			t.Fatalf("%s has no result", algo.GetName())
+		}
+		if result.Summary != "Path cost 4: a -> b -> c -> d" {
+			t.Errorf("%s: summary is %q", algo.GetName(), result.Summary)
+		}
+		if result.EdgeList() != "a -> b, b -> c, c -> d" || result.Metrics[0].String() != "Cost: 4" {
+			t.Errorf("%s: edges %q, metrics %v", algo.GetName(), result.EdgeList(), result.Metrics)
+		}
+	}
+}
+
+func TestTraversalResults(t *testing.T) {
+	g, n := buildGraph(t, "a b, a c, b d")
+	bfs := &BFS{}
+	if err := runAlgorithm(t, bfs, g, n["a"]); err != nil {
+		t.Fatal(err)
+	}
+	if got := bfs.Result().String(); got != "Visited 4 nodes in 3 layers\nVisited: 4\nLayers: 3\nNodes: a, b, c, d\nEdges: a -> b, a -> c, b -> d" {
+		t.Errorf("BFS result is %q", got)
+	}
+	dfs := &DFS{}
+	if err := runAlgorithm(t, dfs, g, n["a"]); err != nil {
+		t.Fatal(err)
+	}
+	if got := dfs.Result(); got.NodeList() != "a, b, d, c" || got.EdgeList() != "a -> b, b -> d, a -> c" {
+		t.Errorf("DFS visited %q over %q", got.NodeList(), got.EdgeList())
+	}
+}
+
+// the path of an Euler result goes along its nodes
+func TestEulerResult(t *testing.T) {
+	g, _ := buildGraph(t, "a b, b c, c a, a d, d e, e a")
+	algo := &Euler{}
+	if err := runAlgorithm(t, algo, g); err != nil {
+		t.Fatal(err)
+	}
+	result := algo.Result()
+	if len(result.Edges) != 6 || len(result.Nodes) != 7 {
+		t.Fatalf("%d nodes and %d edges", len(result.Nodes), len(result.Edges))
+	}
+	for i, e := range result.Edges {
+		if e.Tail != result.Nodes[i] || e.Head != result.Nodes[i+1] {
+			t.Fatalf("edge %d of %q does not follow %q", i, result.EdgeList(), result.NodeList())
+		}
+	}
+}
+
+func TestNoResultBeforeFinish(t *testing.T) {
+	g, n := buildGraph(t, "a b 1, b c 1")
+	algo := &Dijkstra{}
+	algo.Init()
+	algo.NodeSelected(n["a"])
+	algo.NodeSelected(n["c"])
+	if err := algo.Start(g); err != nil {
+		t.Fatal(err)
+	}
+	algo.Update()
+	if algo.Result() != nil {
+		t.Error("a result before the run finished")
+	}
+}
+
+func TestMetricString(t *testing.T) {
+	for metric, want := range map[Metric]string{
+		{"Cost", 12}:     "Cost: 12",
+		{"Length", -3}:   "Length: -3",
+		{"Length", 2.25}: "Length: 2.25",
+	} {
+		if got := metric.String(); got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package algorithm
+
+import (
+	"fmt"
+	"graphographic/graph"
+	"strings"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+type sccFrame struct {
+	node *graph.Node
+	out  []*graph.Edge
+	next int
+}
+
+// Strongly connected components with Tarjan's algorithm. Every update follows one
+// edge of the depth first search or finishes one node, nodes are tagged with their
+// discovery index and lowlink and each finished component gets its own group colour
+type SCC struct {
+	graph      *graph.Graph
+	nodes      []*graph.Node
+	index      map[*graph.Node]int
+	low        map[*graph.Node]int
+	onStack    map[*graph.Node]bool
+	stack      []*graph.Node
+	calls      []sccFrame
+	nextRoot   int
+	components [][]*graph.Node
+	// index in components of each finished node
+	component map[*graph.Node]int
+	done      bool
+}
+
+func (algo *SCC) Init() {
+	algo.graph = nil
+	algo.done = false
+}
+
+func (algo *SCC) GetName() string {
+	return "SCC"
+}
+
+// the whole graph is split, nothing has to be selected
+func (algo *SCC) NodeSelected(node *graph.Node) {}
+func (algo *SCC) UndoSelect()                   {}
+
+func (algo *SCC) Start(g *graph.Graph) error {
+	if g.NodeCount() == 0 {
+		return fmt.Errorf("The graph has no nodes")
+	}
+	algo.graph = g
+	algo.nodes = algo.nodes[:0]
+	for n := range g.Nodes() {
+		algo.nodes = append(algo.nodes, n)
+	}
+	algo.index = make(map[*graph.Node]int, len(algo.nodes))
+	algo.low = make(map[*graph.Node]int, len(algo.nodes))
+	algo.onStack = make(map[*graph.Node]bool, len(algo.nodes))
+	algo.stack = algo.stack[:0]
+	algo.calls = algo.calls[:0]
+	algo.nextRoot = 0
+	algo.components = nil
+	algo.component = make(map[*graph.Node]int, len(algo.nodes))
+	algo.done = false
+	return nil
+}
+
+func (algo *SCC) visit(n *graph.Node) {
+	algo.index[n] = len(algo.index)
+	algo.low[n] = algo.index[n]
+	algo.stack = append(algo.stack, n)
+	algo.onStack[n] = true
+	out := make([]*graph.Edge, 0, n.OutDegree())
+	for e := range n.Out() {
+		out = append(out, e)
+	}
+	algo.calls = append(algo.calls, sccFrame{node: n, out: out})
+	n.Data.Highlighted = true
+	algo.tag(n)
+}
+
+func (algo *SCC) tag(n *graph.Node) {
+	n.Data.Tag = fmt.Sprintf("d=%d low=%d", algo.index[n], algo.low[n])
+}
+
+func (algo *SCC) Update() (bool, error) {
+	if len(algo.calls) == 0 {
+		for ; algo.nextRoot < len(algo.nodes); algo.nextRoot++ {
+			if _, seen := algo.index[algo.nodes[algo.nextRoot]]; !seen {
+				algo.visit(algo.nodes[algo.nextRoot])
+				return true, nil
+			}
+		}
+		algo.done = true
+		return false, nil
+	}
+	top := &algo.calls[len(algo.calls)-1]
+	v := top.node
+	if top.next < len(top.out) {
+		e := top.out[top.next]
+		top.next++
+		w := e.Head
+		if _, seen := algo.index[w]; !seen {
+			e.Data.Explored = true
+			algo.visit(w)
+		} else if algo.onStack[w] {
+			algo.low[v] = min(algo.low[v], algo.index[w])
+			algo.tag(v)
+		}
+		return true, nil
+	}
+	algo.calls = algo.calls[:len(algo.calls)-1]
+	v.Data.Highlighted = false
+	v.Data.Explored = true
+	if len(algo.calls) > 0 {
+		parent := algo.calls[len(algo.calls)-1].node
+		algo.low[parent] = min(algo.low[parent], algo.low[v])
+		algo.tag(parent)
+	}
+	if algo.low[v] == algo.index[v] {
+		algo.popComponent(v)
+	}
+	return true, nil
+}
+
+// the nodes above and including root on the stack form a component
+func (algo *SCC) popComponent(root *graph.Node) {
+	group := len(algo.components) + 1
+	component := make([]*graph.Node, 0)
+	for {
+		n := algo.stack[len(algo.stack)-1]
+		algo.stack = algo.stack[:len(algo.stack)-1]
+		algo.onStack[n] = false
+		n.Data.Group = group
+		algo.component[n] = group - 1
+		component = append(component, n)
+		if n == root {
+			break
+		}
+	}
+	for _, n := range component {
+		for e := range n.Out() {
+			if e.Head.Data.Group == group {
+				e.Data.Group = group
+			}
+		}
+	}
+	algo.components = append(algo.components, component)
+}
+
+func (algo *SCC) Result() *Result {
+	if !algo.done {
+		return nil
+	}
+	return &Result{
+		Summary: fmt.Sprintf("%d strongly connected components", len(algo.components)),
+		Metrics: []Metric{{"Components", float64(len(algo.components))}},
+	}
+}
+
+// Build the condensation: a node for each component placed at its centre and an
+// edge between components wherever the graph had one, with the lowest cost of them
+func (algo *SCC) Build() (graph.Graph, error) {
+	if !algo.done {
+		return graph.Graph{}, fmt.Errorf("Run the algorithm before condensing the graph")
+	}
+	g := graph.New()
+	condensed := make([]*graph.Node, len(algo.components))
+	for i, component := range algo.components {
+		n := graph.NewNode()
+		names := make([]string, 0, len(component))
+		for _, member := range component {
+			names = append(names, member.Content)
+			n.Position = rl.Vector2Add(n.Position, member.Position)
+		}
+		n.Position = rl.Vector2Scale(n.Position, 1/float32(len(component)))
+		n.Content = strings.Join(names, ", ")
+		condensed[i] = g.AddNode(n)
+	}
+	for _, component := range algo.components {
+		for _, member := range component {
+			for e := range member.Out() {
+				tail, head := condensed[algo.component[e.Tail]], condensed[algo.component[e.Head]]
+				if tail == head {
+					continue
+				}
+				if existing := g.EdgeBetween(tail, head); existing != nil {
+					existing.Cost = min(existing.Cost, e.Cost)
+				} else {
+					g.AddEdge(tail, head).Cost = e.Cost
+				}
+			}
+		}
+	}
+	return g, nil
+}
@@ -0,0 +1,55 @@
+package algorithm
+
+import (
+	"testing"
+)
+
+func TestSCCComponents(t *testing.T) {
+	g, n := buildGraph(t, "a b, b c, c a, b d, d e, e f, f d, g f, g h, h g, i")
+	algo := &SCC{}
+	if err := runAlgorithm(t, algo, g); err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{{"a", "b", "c"}, {"d", "e", "f"}, {"g", "h"}, {"i"}}
+	groups := make(map[int]bool)
+	for _, component := range want {
+		group := n[component[0]].Data.Group
+		if group == 0 || groups[group] {
+			t.Fatalf("%v did not get a group of its own", component)
+		}
+		groups[group] = true
+		for _, name := range component {
+			if n[name].Data.Group != group {
+				t.Errorf("%s is not in the group of %s", name, component[0])
+			}
+		}
+	}
+	for e := range g.Edges() {
+		inside := e.Tail.Data.Group == e.Head.Data.Group
+		if inside != (e.Data.Group == e.Tail.Data.Group) {
+			t.Errorf("edge %s -> %s has group %d", e.Tail.Content, e.Head.Content, e.Data.Group)
+		}
+	}
+	if got := algo.Result().Summary; got != "4 strongly connected components" {
+		t.Errorf("report is %q", got)
+	}
+
+	dag, err := algo.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dag.NodeCount() != 4 || dag.EdgeCount() != 2 {
+		t.Fatalf("condensation has %d nodes and %d edges, want 4 and 2", dag.NodeCount(), dag.EdgeCount())
+	}
+	if err := runAlgorithm(t, &TopoSort{}, &dag); err != nil {
+		t.Fatalf("condensation is not acyclic: %v", err)
+	}
+}
+
+func TestSCCBuildNeedsRun(t *testing.T) {
+	algo := &SCC{}
+	algo.Init()
+	if _, err := algo.Build(); err == nil {
+		t.Fatal("expected an error before the algorithm ran")
+	}
+}
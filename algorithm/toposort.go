@@ -0,0 +1,122 @@
+package algorithm
+
+import (
+	"fmt"
+	"graphographic/graph"
+	"slices"
+	"strings"
+)
+
+// Orders the nodes so every edge points forward using Kahn's algorithm. Nodes with no
+// unordered predecessors wait highlighted, every update takes one of them and tags it
+// with its position in the order
+type TopoSort struct {
+	graph *graph.Graph
+	// incoming edges from nodes that are not ordered yet
+	inDegree map[*graph.Node]int
+	ready    []*graph.Node
+	order    int
+	sorted   []*graph.Node
+}
+
+func (algo *TopoSort) Init() {
+	algo.graph = nil
+}
+
+func (algo *TopoSort) GetName() string {
+	return "Topological sort"
+}
+
+// the whole graph is ordered, nothing has to be selected
+func (algo *TopoSort) NodeSelected(node *graph.Node) {}
+func (algo *TopoSort) UndoSelect()                   {}
+
+func (algo *TopoSort) Start(g *graph.Graph) error {
+	if g.NodeCount() == 0 {
+		return fmt.Errorf("The graph has no nodes")
+	}
+	algo.graph = g
+	algo.inDegree = make(map[*graph.Node]int, g.NodeCount())
+	algo.ready = algo.ready[:0]
+	algo.order = 0
+	algo.sorted = algo.sorted[:0]
+	for n := range g.Nodes() {
+		algo.inDegree[n] = n.InDegree()
+		if n.InDegree() == 0 {
+			algo.ready = append(algo.ready, n)
+			n.Data.Highlighted = true
+		}
+	}
+	return nil
+}
+
+func (algo *TopoSort) Update() (bool, error) {
+	if len(algo.ready) == 0 {
+		if algo.order < algo.graph.NodeCount() {
+			return false, algo.findCycle()
+		}
+		return false, nil
+	}
+	n := algo.ready[0]
+	algo.ready = algo.ready[1:]
+	n.Data.Highlighted = false
+	n.Data.Explored = true
+	n.Data.Tag = fmt.Sprintf("%d", algo.order)
+	algo.order++
+	algo.sorted = append(algo.sorted, n)
+	for e := range n.Out() {
+		e.Data.Explored = true
+		algo.inDegree[e.Head]--
+		if algo.inDegree[e.Head] == 0 {
+			algo.ready = append(algo.ready, e.Head)
+			e.Head.Data.Highlighted = true
+		}
+	}
+	return len(algo.ready) > 0 || algo.order < algo.graph.NodeCount(), nil
+}
+
+func (algo *TopoSort) Result() *Result {
+	if algo.graph == nil || len(algo.ready) > 0 || len(algo.sorted) < algo.graph.NodeCount() {
+		return nil
+	}
+	names := make([]string, len(algo.sorted))
+	for i, n := range algo.sorted {
+		names[i] = n.Content
+	}
+	return &Result{
+		Summary: "Order: " + strings.Join(names, ", "),
+		Metrics: []Metric{{"Nodes", float64(len(algo.sorted))}},
+		Nodes:   algo.sorted,
+	}
+}
+
+// Every node left has an incoming edge from another node left, following those
+// edges backwards has to come back to a node seen before
+func (algo *TopoSort) findCycle() error {
+	var n *graph.Node
+	for node := range algo.graph.Nodes() {
+		if algo.inDegree[node] > 0 {
+			n = node
+			break
+		}
+	}
+	// position of each visited node in path
+	seen := make(map[*graph.Node]int)
+	path := make([]*graph.Edge, 0)
+	for {
+		if i, ok := seen[n]; ok {
+			cycle := path[i:]
+			// the edges were collected walking backwards
+			slices.Reverse(cycle)
+			return newCycleError(algo.graph, "The graph has a cycle", cycle)
+		}
+		seen[n] = len(path)
+		for e := range n.In() {
+			if algo.inDegree[e.Tail] > 0 {
+				path = append(path, e)
+				n = e.Tail
+				break
+			}
+		}
+	}
+}
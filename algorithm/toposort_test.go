@@ -0,0 +1,61 @@
+package algorithm
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestTopoSortOrder(t *testing.T) {
+	g, n := buildGraph(t, "shirt tie, tie jacket, trousers shoes, trousers belt, belt jacket, shirt belt, socks shoes, watch")
+	if err := runAlgorithm(t, &TopoSort{}, g); err != nil {
+		t.Fatal(err)
+	}
+	order := make(map[string]int)
+	for name, node := range n {
+		i, err := strconv.Atoi(node.Data.Tag)
+		if err != nil {
+			t.Fatalf("%s has tag %q, want its position", name, node.Data.Tag)
+		}
+		order[name] = i
+	}
+	for e := range g.Edges() {
+		if order[e.Tail.Content] >= order[e.Head.Content] {
+			t.Errorf("%s comes after %s", e.Tail.Content, e.Head.Content)
+		}
+	}
+}
+
+func TestTopoSortCycle(t *testing.T) {
+	tests := []struct {
+		name, edges string
+		cycle       []string
+	}{
+		{"triangle", "start a, a b, b c, c a, c end", []string{"a", "b", "c"}},
+		{"loop", "a b, b b", []string{"b"}},
+	}
+	for _, tt := range tests {
+		g, n := buildGraph(t, tt.edges)
+		err := runAlgorithm(t, &TopoSort{}, g)
+		var cycleErr *CycleError
+		if !errors.As(err, &cycleErr) {
+			t.Fatalf("%s: expected a CycleError, got %v", tt.name, err)
+		}
+		if len(cycleErr.Nodes) != len(tt.cycle) || len(cycleErr.Edges) != len(tt.cycle) {
+			t.Fatalf("%s: got cycle %v", tt.name, err)
+		}
+		for i, e := range cycleErr.Edges {
+			if e.Tail != cycleErr.Nodes[i] || e.Head != cycleErr.Nodes[(i+1)%len(cycleErr.Nodes)] {
+				t.Fatalf("%s: edges of %v do not follow each other", tt.name, err)
+			}
+			if !e.Data.Highlighted {
+				t.Errorf("%s: edge %s -> %s of the cycle is not highlighted", tt.name, e.Tail.Content, e.Head.Content)
+			}
+		}
+		for _, name := range tt.cycle {
+			if !n[name].Data.Highlighted {
+				t.Errorf("%s: %s is on the cycle but not highlighted", tt.name, name)
+			}
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package algorithm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTraversalOrder(t *testing.T) {
+	// edges are followed in the order they were added
+	const tree = "a b, a c, b d, b e, c f, e g"
+	tests := []struct {
+		name  string
+		algo  Algorithm
+		edges string
+		// content of the nodes in visit order and the tag each ends up with
+		order string
+		tags  string
+	}{
+		{"BFS tree", &BFS{}, tree, "a b c d e f g", "0 1 1 2 2 2 3"},
+		{"DFS tree", &DFS{}, tree, "a b d e g c f", "1/14 2/9 3/4 5/8 6/7 10/13 11/12"},
+		// the shorter way round decides the layer
+		{"BFS cycle", &BFS{}, "a b, b c, c d, a d, d a", "a b d c", "0 1 1 2"},
+		{"DFS cycle", &DFS{}, "a b, b c, c d, a d, d a", "a b c d", "1/8 2/7 3/6 4/5"},
+		// x can not be reached from a
+		{"BFS unreachable", &BFS{}, "a b, x a", "a b", "0 1"},
+		{"DFS unreachable", &DFS{}, "a b, x a", "a b", "1/4 2/3"},
+	}
+	for _, test := range tests {
+		g, n := buildGraph(t, test.edges)
+		if err := runAlgorithm(t, test.algo, g, n["a"]); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		var visited []string
+		switch algo := test.algo.(type) {
+		case *BFS:
+			for _, node := range algo.visited {
+				visited = append(visited, node.Content)
+			}
+		case *DFS:
+			for _, node := range algo.visited {
+				visited = append(visited, node.Content)
+			}
+		}
+		if got := strings.Join(visited, " "); got != test.order {
+			t.Errorf("%s: visited %q, want %q", test.name, got, test.order)
+		}
+		order := strings.Fields(test.order)
+		for i, tag := range strings.Fields(test.tags) {
+			if got := n[order[i]].Data.Tag; got != tag {
+				t.Errorf("%s: %s is tagged %q, want %q", test.name, order[i], got, tag)
+			}
+		}
+	}
+}
+
+func TestDFSEdgeClasses(t *testing.T) {
+	// d -> a goes back up the stack, a -> c skips over b to a finished descendant
+	// and e -> c reaches c after it finished on another branch
+	g, n := buildGraph(t, "a b, b c, c d, d a, a c, a e, e c")
+	if err := runAlgorithm(t, &DFS{}, g, n["a"]); err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		tail, head, class string
+	}{
+		{"a", "b", "tree"},
+		{"b", "c", "tree"},
+		{"c", "d", "tree"},
+		{"d", "a", "back"},
+		{"a", "c", "forward"},
+		{"a", "e", "tree"},
+		{"e", "c", "cross"},
+	} {
+		e := g.EdgeBetween(n[test.tail], n[test.head])
+		if e.Data.Tag != test.class {
+			t.Errorf("%s -> %s is a %q edge, want %q", test.tail, test.head, e.Data.Tag, test.class)
+		}
+		if e.Data.Explored != (test.class == "tree") {
+			t.Errorf("%s -> %s explored: %v", test.tail, test.head, e.Data.Explored)
+		}
+	}
+}
@@ -0,0 +1,349 @@
+package algorithm
+
+import (
+	"cmp"
+	"fmt"
+	"graphographic/graph"
+	"slices"
+)
+
+// How a travelling salesman tour is built
+type TSPMethod int
+
+const (
+	// always on to the closest node not visited yet
+	TSPNearestNeighbour TSPMethod = iota
+	// a nearest neighbour tour improved by reversing parts of it while that makes
+	// it shorter
+	TSPTwoOpt
+	// spanning tree, its odd nodes matched greedily and an Eulerian circuit of both
+	// with nodes seen before skipped
+	TSPChristofides
+	tspMethodCount
+)
+
+func (m TSPMethod) String() string {
+	switch m {
+	case TSPNearestNeighbour:
+		return "nearest neighbour"
+	case TSPTwoOpt:
+		return "2-opt"
+	case TSPChristofides:
+		return "Christofides"
+	}
+	return "unknown"
+}
+
+// Travelling salesman heuristics, a short tour through all the nodes. Distances are
+// the costs of the edges between nodes, which then have to be connected with every
+// other node, or the euclidean distances between their positions. Every update
+// changes the tour, which is drawn over the graph
+type TSP struct {
+	Method    TSPMethod
+	Euclidean bool
+	start     *graph.Node
+	graph     *graph.Graph
+	nodes     []*graph.Node
+	// cheapest edge cost between two nodes in either direction
+	costs map[[2]*graph.Node]int32
+	tour  []*graph.Node
+	// the Christofides tour, added to the shown tour one node per update
+	plan []*graph.Node
+	done bool
+}
+
+func (algo *TSP) Init() {
+	algo.start = nil
+	algo.done = false
+}
+
+func (algo *TSP) GetName() string {
+	return "TSP"
+}
+
+// the metric changes first, then the method
+func (algo *TSP) NextOption() {
+	algo.Euclidean = !algo.Euclidean
+	if !algo.Euclidean {
+		algo.Method = (algo.Method + 1) % tspMethodCount
+	}
+}
+
+func (algo *TSP) OptionName() string {
+	if algo.Euclidean {
+		return algo.Method.String() + ", euclidean"
+	}
+	return algo.Method.String() + ", cost"
+}
+
+// the tour starts at the selected node, or the first one
+func (algo *TSP) NodeSelected(node *graph.Node) {
+	algo.start = node
+}
+
+func (algo *TSP) UndoSelect() {
+	algo.start = nil
+}
+
+func (algo *TSP) Start(g *graph.Graph) error {
+	if g.NodeCount() == 0 {
+		return fmt.Errorf("The graph has no nodes")
+	}
+	algo.graph = g
+	algo.nodes = algo.nodes[:0]
+	for n := range g.Nodes() {
+		algo.nodes = append(algo.nodes, n)
+	}
+	if !algo.Euclidean {
+		algo.costs = make(map[[2]*graph.Node]int32, g.EdgeCount())
+		for e := range g.Edges() {
+			for _, key := range [][2]*graph.Node{{e.Tail, e.Head}, {e.Head, e.Tail}} {
+				if c, ok := algo.costs[key]; !ok || e.Cost < c {
+					algo.costs[key] = e.Cost
+				}
+			}
+		}
+		for i, a := range algo.nodes {
+			for _, b := range algo.nodes[i+1:] {
+				if _, ok := algo.costs[[2]*graph.Node{a, b}]; !ok {
+					return fmt.Errorf("%s and %s are not connected, use the euclidean distance or connect every two nodes", a.Content, b.Content)
+				}
+			}
+		}
+	}
+	start := algo.start
+	if start == nil {
+		start = algo.nodes[0]
+	}
+	algo.tour = append(algo.tour[:0], start)
+	algo.plan = nil
+	switch algo.Method {
+	case TSPTwoOpt:
+		algo.tour = algo.nearestNeighbourTour(start)
+	case TSPChristofides:
+		algo.plan = algo.christofidesTour(start)[1:]
+	}
+	algo.done = false
+	algo.mark()
+	return nil
+}
+
+func (algo *TSP) dist(a, b *graph.Node) float64 {
+	if algo.Euclidean {
+		return distance(a, b)
+	}
+	if a == b {
+		return 0
+	}
+	return float64(algo.costs[[2]*graph.Node{a, b}])
+}
+
+func (algo *TSP) length() float64 {
+	total := 0.0
+	for i, n := range algo.tour {
+		total += algo.dist(n, algo.tour[(i+1)%len(algo.tour)])
+	}
+	return total
+}
+
+func (algo *TSP) nearest(from *graph.Node, visited map[*graph.Node]bool) *graph.Node {
+	var best *graph.Node
+	for _, n := range algo.nodes {
+		if !visited[n] && (best == nil || algo.dist(from, n) < algo.dist(from, best)) {
+			best = n
+		}
+	}
+	return best
+}
+
+func (algo *TSP) nearestNeighbourTour(start *graph.Node) []*graph.Node {
+	tour := []*graph.Node{start}
+	visited := map[*graph.Node]bool{start: true}
+	for len(tour) < len(algo.nodes) {
+		n := algo.nearest(tour[len(tour)-1], visited)
+		visited[n] = true
+		tour = append(tour, n)
+	}
+	return tour
+}
+
+func (algo *TSP) christofidesTour(start *graph.Node) []*graph.Node {
+	// Prim's spanning tree over all pairs of nodes
+	adjacent := make(map[*graph.Node][]*graph.Node, len(algo.nodes))
+	inTree := map[*graph.Node]bool{start: true}
+	closest := make(map[*graph.Node]*graph.Node, len(algo.nodes))
+	for _, n := range algo.nodes {
+		closest[n] = start
+	}
+	for len(inTree) < len(algo.nodes) {
+		var next *graph.Node
+		for _, n := range algo.nodes {
+			if !inTree[n] && (next == nil || algo.dist(n, closest[n]) < algo.dist(next, closest[next])) {
+				next = n
+			}
+		}
+		inTree[next] = true
+		adjacent[next] = append(adjacent[next], closest[next])
+		adjacent[closest[next]] = append(adjacent[closest[next]], next)
+		for _, n := range algo.nodes {
+			if !inTree[n] && algo.dist(n, next) < algo.dist(n, closest[n]) {
+				closest[n] = next
+			}
+		}
+	}
+	// the odd nodes are paired closest first instead of by a minimum matching
+	odd := make([]*graph.Node, 0)
+	for _, n := range algo.nodes {
+		if len(adjacent[n])%2 == 1 {
+			odd = append(odd, n)
+		}
+	}
+	pairs := make([][2]*graph.Node, 0, len(odd)*len(odd)/2)
+	for i, a := range odd {
+		for _, b := range odd[i+1:] {
+			pairs = append(pairs, [2]*graph.Node{a, b})
+		}
+	}
+	slices.SortStableFunc(pairs, func(p, q [2]*graph.Node) int {
+		return cmp.Compare(algo.dist(p[0], p[1]), algo.dist(q[0], q[1]))
+	})
+	matched := make(map[*graph.Node]bool, len(odd))
+	for _, p := range pairs {
+		if !matched[p[0]] && !matched[p[1]] {
+			matched[p[0]], matched[p[1]] = true, true
+			adjacent[p[0]] = append(adjacent[p[0]], p[1])
+			adjacent[p[1]] = append(adjacent[p[1]], p[0])
+		}
+	}
+	// Hierholzer's circuit, every node is kept only the first time it comes up
+	tour := make([]*graph.Node, 0, len(algo.nodes))
+	visited := make(map[*graph.Node]bool, len(algo.nodes))
+	stack := []*graph.Node{start}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		if len(adjacent[n]) == 0 {
+			stack = stack[:len(stack)-1]
+			if !visited[n] {
+				visited[n] = true
+				tour = append(tour, n)
+			}
+			continue
+		}
+		m := adjacent[n][len(adjacent[n])-1]
+		adjacent[n] = adjacent[n][:len(adjacent[n])-1]
+		adjacent[m] = slices.Delete(adjacent[m], slices.Index(adjacent[m], n), slices.Index(adjacent[m], n)+1)
+		stack = append(stack, m)
+	}
+	// the circuit comes out backwards, which is the same tour, only the start has
+	// to stay first
+	slices.Reverse(tour)
+	i := slices.Index(tour, start)
+	return append(tour[i:], tour[:i]...)
+}
+
+// Reverses the part of the tour that makes it shortest, false when no reversal
+// makes it any shorter
+func (algo *TSP) twoOpt() bool {
+	n := len(algo.tour)
+	bestGain, bestI, bestJ := 1e-9, -1, -1
+	for i := 0; i < n-1; i++ {
+		for j := i + 2; j < n; j++ {
+			a, b := algo.tour[i], algo.tour[i+1]
+			c, d := algo.tour[j], algo.tour[(j+1)%n]
+			if a == d {
+				continue
+			}
+			if gain := algo.dist(a, b) + algo.dist(c, d) - algo.dist(a, c) - algo.dist(b, d); gain > bestGain {
+				bestGain, bestI, bestJ = gain, i, j
+			}
+		}
+	}
+	if bestI < 0 {
+		return false
+	}
+	slices.Reverse(algo.tour[bestI+1 : bestJ+1])
+	return true
+}
+
+// edges of the graph between nodes that follow each other in the tour, in either
+// direction, the closing one only once the tour is complete
+func (algo *TSP) tourEdges() []*graph.Edge {
+	edges := make([]*graph.Edge, 0, len(algo.tour))
+	for i, n := range algo.tour {
+		if i+1 == len(algo.tour) && !algo.done {
+			break
+		}
+		next := algo.tour[(i+1)%len(algo.tour)]
+		if e := algo.graph.EdgeBetween(n, next); e != nil {
+			edges = append(edges, e)
+		} else if e := algo.graph.EdgeBetween(next, n); e != nil {
+			edges = append(edges, e)
+		}
+	}
+	return edges
+}
+
+// highlights the edges along the tour, the nodes are tagged with their position
+func (algo *TSP) mark() {
+	for e := range algo.graph.Edges() {
+		e.Data.Highlighted = false
+	}
+	for n := range algo.graph.Nodes() {
+		n.Data.Explored = false
+		n.Data.Tag = ""
+	}
+	for i, n := range algo.tour {
+		n.Data.Explored = true
+		n.Data.Tag = fmt.Sprint(i + 1)
+	}
+	for _, e := range algo.tourEdges() {
+		e.Data.Highlighted = true
+	}
+}
+
+func (algo *TSP) Update() (bool, error) {
+	switch algo.Method {
+	case TSPNearestNeighbour:
+		visited := make(map[*graph.Node]bool, len(algo.tour))
+		for _, n := range algo.tour {
+			visited[n] = true
+		}
+		if n := algo.nearest(algo.tour[len(algo.tour)-1], visited); n != nil {
+			algo.tour = append(algo.tour, n)
+		} else {
+			algo.done = true
+		}
+	case TSPTwoOpt:
+		algo.done = !algo.twoOpt()
+	case TSPChristofides:
+		if len(algo.plan) > 0 {
+			algo.tour = append(algo.tour, algo.plan[0])
+			algo.plan = algo.plan[1:]
+		} else {
+			algo.done = true
+		}
+	}
+	algo.mark()
+	return !algo.done, nil
+}
+
+func (algo *TSP) Tour() ([]*graph.Node, bool) {
+	return algo.tour, algo.done
+}
+
+func (algo *TSP) Result() *Result {
+	if !algo.done {
+		return nil
+	}
+	length := algo.length()
+	result := &Result{
+		Summary: fmt.Sprintf("Tour length: %.0f", length),
+		Metrics: []Metric{{"Length", length}},
+		Nodes:   algo.tour,
+	}
+	if algo.Euclidean {
+		result.Summary = fmt.Sprintf("Tour length: %.1f", length)
+	}
+	result.Edges = algo.tourEdges()
+	return result
+}
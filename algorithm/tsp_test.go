@@ -0,0 +1,84 @@
+package algorithm
+
+import (
+	"fmt"
+	"graphographic/graph"
+	"math/rand"
+	"strings"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// nodes at random positions with no edges
+func scatteredGraph(t *testing.T, count int) *graph.Graph {
+	t.Helper()
+	rng := rand.New(rand.NewSource(1))
+	names := make([]string, count)
+	for i := range names {
+		names[i] = fmt.Sprint(i)
+	}
+	g, _ := buildGraph(t, strings.Join(names, ","))
+	for n := range g.Nodes() {
+		n.Position = rl.Vector2{X: rng.Float32() * 1000, Y: rng.Float32() * 1000}
+	}
+	return g
+}
+
+func tourLength(t *testing.T, algo *TSP, g *graph.Graph) float64 {
+	t.Helper()
+	if err := runAlgorithm(t, algo, g); err != nil {
+		t.Fatal(err)
+	}
+	tour, closed := algo.Tour()
+	if !closed || len(tour) != g.NodeCount() {
+		t.Fatalf("%s: tour of %d nodes, closed: %v", algo.OptionName(), len(tour), closed)
+	}
+	seen := make(map[*graph.Node]bool)
+	for _, n := range tour {
+		if seen[n] {
+			t.Fatalf("%s: %s is in the tour twice", algo.OptionName(), n.Content)
+		}
+		seen[n] = true
+	}
+	return algo.length()
+}
+
+func TestTSPSquare(t *testing.T) {
+	// corners of a square created so that going in order crosses over
+	g, n := buildGraph(t, "a, c, b, d")
+	n["a"].Position = rl.Vector2{X: 0, Y: 0}
+	n["b"].Position = rl.Vector2{X: 10, Y: 0}
+	n["c"].Position = rl.Vector2{X: 10, Y: 10}
+	n["d"].Position = rl.Vector2{X: 0, Y: 10}
+	for method := range tspMethodCount {
+		algo := &TSP{Method: method, Euclidean: true}
+		tourLength(t, algo, g)
+		if got := algo.Result().Summary; got != "Tour length: 40.0" {
+			t.Errorf("%s: report is %q", algo.OptionName(), got)
+		}
+	}
+}
+
+func TestTSPHeuristics(t *testing.T) {
+	g := scatteredGraph(t, 40)
+	nearest := tourLength(t, &TSP{Method: TSPNearestNeighbour, Euclidean: true}, g)
+	twoOpt := tourLength(t, &TSP{Method: TSPTwoOpt, Euclidean: true}, g)
+	tourLength(t, &TSP{Method: TSPChristofides, Euclidean: true}, g)
+	if twoOpt > nearest {
+		t.Errorf("2-opt made the tour longer, %.1f from %.1f", twoOpt, nearest)
+	}
+}
+
+func TestTSPCosts(t *testing.T) {
+	g, _ := buildGraph(t, "a b 1, b c 1, c a 5, a d 1, b d 5, d c 1")
+	algo := &TSP{Method: TSPTwoOpt}
+	tourLength(t, algo, g)
+	if got := algo.Result().Summary; got != "Tour length: 4" {
+		t.Errorf("report is %q", got)
+	}
+	g, _ = buildGraph(t, "a b 1, b c 1")
+	if err := runAlgorithm(t, &TSP{}, g); err == nil {
+		t.Error("a tour by cost was made without an edge between a and c")
+	}
+}
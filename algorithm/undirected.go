@@ -0,0 +1,49 @@
+package algorithm
+
+import (
+	"graphographic/graph"
+)
+
+// Edges of g with the undirected pairs counted once, for
+// the algorithms that ignore the direction of edges
+func undirectedEdges(g *graph.Graph) []*graph.Edge {
+	edges := make([]*graph.Edge, 0, g.EdgeCount())
+	paired := make(map[*graph.Edge]bool)
+	for e := range g.Edges() {
+		if paired[e] {
+			continue
+		}
+		if opp := e.Pair(); opp != nil {
+			paired[opp] = true
+		}
+		edges = append(edges, e)
+	}
+	return edges
+}
+
+// sets the state of an edge and of its pair if it has one
+func markUndirected(e *graph.Edge, explored, highlighted bool) {
+	e.Data.Explored, e.Data.Highlighted = explored, highlighted
+	if opp := e.Pair(); opp != nil {
+		opp.Data.Explored, opp.Data.Highlighted = explored, highlighted
+	}
+}
+
+type undirectedLink struct {
+	// representative of an undirected pair or a lone edge
+	edge  *graph.Edge
+	other *graph.Node
+}
+
+// Neighbours of every node with edge directions ignored, loops are left out
+func undirectedLinks(g *graph.Graph) map[*graph.Node][]undirectedLink {
+	links := make(map[*graph.Node][]undirectedLink, g.NodeCount())
+	for _, e := range undirectedEdges(g) {
+		if e.Tail == e.Head {
+			continue
+		}
+		links[e.Tail] = append(links[e.Tail], undirectedLink{e, e.Head})
+		links[e.Head] = append(links[e.Head], undirectedLink{e, e.Tail})
+	}
+	return links
+}
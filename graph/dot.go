@@ -0,0 +1,874 @@
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Graphviz DOT support. Graphographic has no undirected edges, an undirected
+// DOT edge a -- b becomes a pair of edges a -> b and b -> a just like the ones
+// created in UNDIRECTED connect mode. Node labels map to Node.Content, the edge
+// weight (or a numeric label) to Edge.Cost and pos to Node.Position. Every other
+// attribute is stored in the Attrs of its node, edge or graph under "dot:<name>"
+// and written back out, HTML strings keep their angle brackets there. Attributes
+// of subgraphs are not kept since the subgraphs themselves are not.
+//
+// Only a graph made of undirected pairs is written as an undirected graph. Edges
+// carry their ID as id="e<ID>" (gg_id when the file had an id of its own), the ID
+// of their pair as gg_pair and a negative cost, which Graphviz does not allow as a
+// weight, as gg_cost. The graph attributes gg_last_node and gg_last_edge keep the
+// highest IDs handed out.
+
+const dotPrefix = "dot:"
+
+type dotTokenKind int
+
+const (
+	dotEOF dotTokenKind = iota
+	dotID
+	dotLBrace
+	dotRBrace
+	dotLBracket
+	dotRBracket
+	dotEqual
+	dotSemicolon
+	dotComma
+	dotColon
+	dotEdgeOp
+)
+
+type dotToken struct {
+	kind dotTokenKind
+	text string
+	line int
+	// quoted and HTML strings are never keywords
+	quoted bool
+	html   bool
+}
+
+type dotLexer struct {
+	src  []rune
+	pos  int
+	line int
+}
+
+func (lex *dotLexer) peekRune(offset int) rune {
+	if lex.pos+offset >= len(lex.src) {
+		return 0
+	}
+	return lex.src[lex.pos+offset]
+}
+
+func (lex *dotLexer) skipSpaceAndComments() error {
+	atLineStart := lex.pos == 0
+	for lex.pos < len(lex.src) {
+		r := lex.src[lex.pos]
+		switch {
+		case r == '\n':
+			lex.line++
+			lex.pos++
+			atLineStart = true
+		case unicode.IsSpace(r):
+			lex.pos++
+		case r == '#' && atLineStart:
+			// preprocessor output, ignored like Graphviz does
+			for lex.pos < len(lex.src) && lex.src[lex.pos] != '\n' {
+				lex.pos++
+			}
+		case r == '/' && lex.peekRune(1) == '/':
+			for lex.pos < len(lex.src) && lex.src[lex.pos] != '\n' {
+				lex.pos++
+			}
+		case r == '/' && lex.peekRune(1) == '*':
+			start := lex.line
+			lex.pos += 2
+			for lex.pos < len(lex.src) && !(lex.src[lex.pos] == '*' && lex.peekRune(1) == '/') {
+				if lex.src[lex.pos] == '\n' {
+					lex.line++
+				}
+				lex.pos++
+			}
+			if lex.pos >= len(lex.src) {
+				return fmt.Errorf("DOT line %d: unterminated comment", start)
+			}
+			lex.pos += 2
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+func (lex *dotLexer) next() (dotToken, error) {
+	if err := lex.skipSpaceAndComments(); err != nil {
+		return dotToken{}, err
+	}
+	if lex.pos >= len(lex.src) {
+		return dotToken{kind: dotEOF, line: lex.line}, nil
+	}
+	r := lex.src[lex.pos]
+	tok := dotToken{line: lex.line}
+	single := map[rune]dotTokenKind{
+		'{': dotLBrace, '}': dotRBrace, '[': dotLBracket, ']': dotRBracket,
+		'=': dotEqual, ';': dotSemicolon, ',': dotComma, ':': dotColon,
+	}
+	if kind, ok := single[r]; ok {
+		lex.pos++
+		tok.kind = kind
+		tok.text = string(r)
+		return tok, nil
+	}
+	switch {
+	case r == '-' && (lex.peekRune(1) == '>' || lex.peekRune(1) == '-'):
+		tok.kind = dotEdgeOp
+		tok.text = string(lex.src[lex.pos : lex.pos+2])
+		lex.pos += 2
+		return tok, nil
+	case r == '"':
+		text, err := lex.quoted()
+		if err != nil {
+			return tok, err
+		}
+		// "a" + "b" concatenates
+		for {
+			save, saveLine := lex.pos, lex.line
+			if err := lex.skipSpaceAndComments(); err != nil {
+				return tok, err
+			}
+			if lex.peekRune(0) != '+' {
+				lex.pos, lex.line = save, saveLine
+				break
+			}
+			lex.pos++
+			if err := lex.skipSpaceAndComments(); err != nil {
+				return tok, err
+			}
+			if lex.peekRune(0) != '"' {
+				return tok, fmt.Errorf("DOT line %d: expected a string after '+'", lex.line)
+			}
+			more, err := lex.quoted()
+			if err != nil {
+				return tok, err
+			}
+			text += more
+		}
+		tok.kind = dotID
+		tok.text = text
+		tok.quoted = true
+		return tok, nil
+	case r == '<':
+		depth := 0
+		start := lex.pos
+		for ; lex.pos < len(lex.src); lex.pos++ {
+			switch lex.src[lex.pos] {
+			case '<':
+				depth++
+			case '>':
+				depth--
+			case '\n':
+				lex.line++
+			}
+			if depth == 0 {
+				break
+			}
+		}
+		if depth != 0 {
+			return tok, fmt.Errorf("DOT line %d: unterminated HTML string", tok.line)
+		}
+		lex.pos++
+		tok.kind = dotID
+		tok.text = string(lex.src[start+1 : lex.pos-1])
+		tok.quoted = true
+		tok.html = true
+		return tok, nil
+	case r == '-' || r == '.' || unicode.IsDigit(r):
+		start := lex.pos
+		lex.pos++
+		for lex.pos < len(lex.src) && (unicode.IsDigit(lex.src[lex.pos]) || lex.src[lex.pos] == '.') {
+			lex.pos++
+		}
+		tok.kind = dotID
+		tok.text = string(lex.src[start:lex.pos])
+		if _, err := strconv.ParseFloat(tok.text, 64); err != nil {
+			return tok, fmt.Errorf("DOT line %d: malformed number %q", tok.line, tok.text)
+		}
+		return tok, nil
+	case r == '_' || unicode.IsLetter(r):
+		start := lex.pos
+		for lex.pos < len(lex.src) && (lex.src[lex.pos] == '_' || unicode.IsLetter(lex.src[lex.pos]) || unicode.IsDigit(lex.src[lex.pos])) {
+			lex.pos++
+		}
+		tok.kind = dotID
+		tok.text = string(lex.src[start:lex.pos])
+		return tok, nil
+	}
+	return tok, fmt.Errorf("DOT line %d: unexpected character %q", tok.line, r)
+}
+
+// reads a double quoted string, only \" is an escape at this level. Other escapes
+// are left for dotUnescape, \\ is kept whole so it can not escape a quote
+func (lex *dotLexer) quoted() (string, error) {
+	start := lex.line
+	lex.pos++
+	var sb strings.Builder
+	for lex.pos < len(lex.src) {
+		r := lex.src[lex.pos]
+		switch {
+		case r == '"':
+			lex.pos++
+			return sb.String(), nil
+		case r == '\\' && lex.peekRune(1) == '"':
+			sb.WriteRune('"')
+			lex.pos += 2
+		case r == '\\' && lex.peekRune(1) == '\\':
+			sb.WriteString(`\\`)
+			lex.pos += 2
+		case r == '\\' && lex.peekRune(1) == '\n':
+			// line continuation
+			lex.line++
+			lex.pos += 2
+		default:
+			if r == '\n' {
+				lex.line++
+			}
+			sb.WriteRune(r)
+			lex.pos++
+		}
+	}
+	return "", fmt.Errorf("DOT line %d: unterminated string", start)
+}
+
+type dotValue struct {
+	text string
+	html bool
+}
+
+// the value as it is stored in Attrs, HTML strings keep their angle brackets
+func (v dotValue) kept() string {
+	if v.html {
+		return "<" + v.text + ">"
+	}
+	return v.text
+}
+
+type dotAttrs map[string]dotValue
+
+func (a dotAttrs) merged(b dotAttrs) dotAttrs {
+	ret := make(dotAttrs, len(a)+len(b))
+	for k, v := range a {
+		ret[k] = v
+	}
+	for k, v := range b {
+		ret[k] = v
+	}
+	return ret
+}
+
+type dotScope struct {
+	nodeDefaults dotAttrs
+	edgeDefaults dotAttrs
+	// inside a subgraph, graph attributes are dropped
+	nested bool
+}
+
+type dotParser struct {
+	lex      dotLexer
+	tok      dotToken
+	directed bool
+	g        *Graph
+	nodes    map[string]*Node
+	// nodes that were given a pos attribute
+	placed map[*Node]bool
+	// directed edges and the ID of their pair, paired once every edge is read
+	pending []pendingPair
+}
+
+func (p *dotParser) advance() error {
+	tok, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *dotParser) isKeyword(kw string) bool {
+	return p.tok.kind == dotID && !p.tok.quoted && strings.EqualFold(p.tok.text, kw)
+}
+
+func (p *dotParser) expect(kind dotTokenKind, what string) (dotToken, error) {
+	tok := p.tok
+	if tok.kind != kind {
+		return tok, p.unexpected(what)
+	}
+	return tok, p.advance()
+}
+
+func (p *dotParser) unexpected(what string) error {
+	if p.tok.kind == dotEOF {
+		return fmt.Errorf("DOT line %d: expected %s, got end of file", p.tok.line, what)
+	}
+	return fmt.Errorf("DOT line %d: expected %s, got %q", p.tok.line, what, p.tok.text)
+}
+
+func (p *dotParser) parseGraph() error {
+	if p.isKeyword("strict") {
+		if err := p.advance(); err != nil {
+			return err
+		}
+	}
+	switch {
+	case p.isKeyword("digraph"):
+		p.directed = true
+	case p.isKeyword("graph"):
+		p.directed = false
+	default:
+		return p.unexpected("'graph' or 'digraph'")
+	}
+	if err := p.advance(); err != nil {
+		return err
+	}
+	if p.tok.kind == dotID {
+		if err := p.advance(); err != nil {
+			return err
+		}
+	}
+	if _, err := p.expect(dotLBrace, "'{'"); err != nil {
+		return err
+	}
+	scope := dotScope{nodeDefaults: dotAttrs{}, edgeDefaults: dotAttrs{}}
+	if _, err := p.parseStmtList(&scope); err != nil {
+		return err
+	}
+	if _, err := p.expect(dotRBrace, "'}'"); err != nil {
+		return err
+	}
+	if p.tok.kind != dotEOF {
+		return p.unexpected("end of file")
+	}
+	return nil
+}
+
+// parses statements until a closing brace, returns the names of all nodes mentioned
+func (p *dotParser) parseStmtList(scope *dotScope) ([]string, error) {
+	mentioned := make([]string, 0)
+	for p.tok.kind != dotRBrace && p.tok.kind != dotEOF {
+		names, err := p.parseStmt(scope)
+		if err != nil {
+			return nil, err
+		}
+		mentioned = append(mentioned, names...)
+		if p.tok.kind == dotSemicolon {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return mentioned, nil
+}
+
+func (p *dotParser) parseStmt(scope *dotScope) ([]string, error) {
+	if p.isKeyword("graph") || p.isKeyword("node") || p.isKeyword("edge") {
+		kind := strings.ToLower(p.tok.text)
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		attrs, err := p.parseAttrLists()
+		if err != nil {
+			return nil, err
+		}
+		switch kind {
+		case "graph":
+			p.graphAttrs(scope, attrs)
+		case "node":
+			scope.nodeDefaults = scope.nodeDefaults.merged(attrs)
+		case "edge":
+			scope.edgeDefaults = scope.edgeDefaults.merged(attrs)
+		}
+		return nil, nil
+	}
+	operand, err := p.parseOperand(scope)
+	if err != nil {
+		return nil, err
+	}
+	if operand.single != "" && p.tok.kind == dotEqual {
+		// graph attribute assignment ID = ID
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		value, err := p.expect(dotID, "attribute value")
+		if err != nil {
+			return nil, err
+		}
+		p.graphAttrs(scope, dotAttrs{operand.single: dotValue{value.text, value.html}})
+		return nil, nil
+	}
+	if p.tok.kind != dotEdgeOp {
+		if operand.single != "" {
+			attrs, err := p.parseAttrLists()
+			if err != nil {
+				return nil, err
+			}
+			p.declareNode(operand.single, scope.nodeDefaults, attrs)
+		}
+		return operand.names, nil
+	}
+	chain := [][]string{operand.names}
+	for p.tok.kind == dotEdgeOp {
+		if p.tok.text == "->" && !p.directed {
+			return nil, fmt.Errorf("DOT line %d: '->' used in an undirected graph", p.tok.line)
+		}
+		if p.tok.text == "--" && p.directed {
+			return nil, fmt.Errorf("DOT line %d: '--' used in a directed graph", p.tok.line)
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		next, err := p.parseOperand(scope)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, next.names)
+	}
+	attrs, err := p.parseAttrLists()
+	if err != nil {
+		return nil, err
+	}
+	spec, err := dotReadEdge(scope.edgeDefaults.merged(attrs))
+	if err != nil {
+		return nil, fmt.Errorf("DOT line %d: %w", p.tok.line, err)
+	}
+	mentioned := make([]string, 0)
+	for i := 0; i+1 < len(chain); i++ {
+		for _, tailName := range chain[i] {
+			for _, headName := range chain[i+1] {
+				tail, head := p.nodes[tailName], p.nodes[headName]
+				edge := p.g.AddEdgeID(spec.id, tail, head)
+				edge.Cost = spec.cost
+				edge.Attrs = copyAttributes(spec.attrs)
+				switch {
+				case !p.directed && tail == head:
+					p.g.PairEdges(edge, edge)
+				case !p.directed:
+					reverse := p.g.AddEdgeID(spec.pair, head, tail)
+					reverse.Cost = spec.cost
+					reverse.Attrs = copyAttributes(spec.attrs)
+					p.g.PairEdges(edge, reverse)
+				case spec.pair != 0:
+					p.pending = append(p.pending, pendingPair{edge, spec.pair})
+				}
+			}
+		}
+	}
+	for _, names := range chain {
+		mentioned = append(mentioned, names...)
+	}
+	return mentioned, nil
+}
+
+type dotOperand struct {
+	// set when the operand is a plain node id
+	single string
+	names  []string
+}
+
+func (p *dotParser) parseOperand(scope *dotScope) (dotOperand, error) {
+	if p.isKeyword("subgraph") || p.tok.kind == dotLBrace {
+		if p.isKeyword("subgraph") {
+			if err := p.advance(); err != nil {
+				return dotOperand{}, err
+			}
+			if p.tok.kind == dotID {
+				if err := p.advance(); err != nil {
+					return dotOperand{}, err
+				}
+			}
+		}
+		if _, err := p.expect(dotLBrace, "'{'"); err != nil {
+			return dotOperand{}, err
+		}
+		inner := *scope
+		inner.nested = true
+		names, err := p.parseStmtList(&inner)
+		if err != nil {
+			return dotOperand{}, err
+		}
+		if _, err := p.expect(dotRBrace, "'}'"); err != nil {
+			return dotOperand{}, err
+		}
+		return dotOperand{names: names}, nil
+	}
+	tok, err := p.expect(dotID, "a node id")
+	if err != nil {
+		return dotOperand{}, err
+	}
+	// ports are irrelevant for Graphographic, skip them
+	for p.tok.kind == dotColon {
+		if err := p.advance(); err != nil {
+			return dotOperand{}, err
+		}
+		if _, err := p.expect(dotID, "a port"); err != nil {
+			return dotOperand{}, err
+		}
+	}
+	if p.tok.kind != dotEqual {
+		p.declareNode(tok.text, scope.nodeDefaults, nil)
+	}
+	return dotOperand{single: tok.text, names: []string{tok.text}}, nil
+}
+
+func (p *dotParser) parseAttrLists() (dotAttrs, error) {
+	attrs := dotAttrs{}
+	for p.tok.kind == dotLBracket {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		for p.tok.kind != dotRBracket {
+			key, err := p.expect(dotID, "an attribute name")
+			if err != nil {
+				return nil, err
+			}
+			value := dotValue{text: "true"}
+			if p.tok.kind == dotEqual {
+				if err := p.advance(); err != nil {
+					return nil, err
+				}
+				tok, err := p.expect(dotID, "an attribute value")
+				if err != nil {
+					return nil, err
+				}
+				value = dotValue{tok.text, tok.html}
+			}
+			attrs[key.text] = value
+			if p.tok.kind == dotComma || p.tok.kind == dotSemicolon {
+				if err := p.advance(); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	return attrs, nil
+}
+
+// creates the node on first mention and applies the attributes
+func (p *dotParser) declareNode(name string, defaults, attrs dotAttrs) {
+	node, ok := p.nodes[name]
+	if !ok {
+		n := NewNode()
+		n.ID = dotNodeID(name)
+		n.Content = name
+		node = p.g.AddNode(n)
+		p.nodes[name] = node
+		attrs = defaults.merged(attrs)
+	}
+	if label, ok := attrs["label"]; ok {
+		node.Content = dotUnescape(label.text, name)
+	}
+	if pos, ok := attrs["pos"]; ok {
+		if v, ok := dotParsePos(pos.text); ok {
+			node.Position = v
+			p.placed[node] = true
+		}
+	}
+	for k, v := range dotUnknown(attrs, "label", "pos") {
+		if node.Attrs == nil {
+			node.Attrs = make(Attributes)
+		}
+		node.Attrs[k] = v
+	}
+}
+
+// attributes of the graph itself, those of subgraphs are dropped
+func (p *dotParser) graphAttrs(scope *dotScope, attrs dotAttrs) {
+	if scope.nested {
+		return
+	}
+	// the highest IDs handed out, see Graph.LastIDs
+	if last, err := strconv.ParseUint(attrs["gg_last_node"].text, 10, 64); err == nil {
+		p.g.ReserveIDs(NodeID(last), 0)
+	}
+	if last, err := strconv.ParseUint(attrs["gg_last_edge"].text, 10, 64); err == nil {
+		p.g.ReserveIDs(0, EdgeID(last))
+	}
+	for k, v := range dotUnknown(attrs, "gg_last_node", "gg_last_edge") {
+		if p.g.Attrs == nil {
+			p.g.Attrs = make(Attributes)
+		}
+		p.g.Attrs[k] = v
+	}
+}
+
+// the attributes except the understood ones, prefixed for Attrs
+func dotUnknown(attrs dotAttrs, understood ...string) Attributes {
+	var ret Attributes
+	for k, v := range attrs {
+		if slices.Contains(understood, k) {
+			continue
+		}
+		if ret == nil {
+			ret = make(Attributes)
+		}
+		ret[dotPrefix+k] = v.kept()
+	}
+	return ret
+}
+
+// names written by WriteDOT carry the node ID, other names get a fresh one
+func dotNodeID(name string) NodeID {
+	id, err := strconv.ParseUint(strings.TrimPrefix(name, "n"), 10, 64)
+	if err != nil || !strings.HasPrefix(name, "n") {
+		return 0
+	}
+	return NodeID(id)
+}
+
+// edge ids written by WriteDOT are "e<ID>", others get a fresh ID
+func dotEdgeRef(ref string) EdgeID {
+	id, err := strconv.ParseUint(strings.TrimPrefix(ref, "e"), 10, 64)
+	if err != nil || !strings.HasPrefix(ref, "e") {
+		return 0
+	}
+	return EdgeID(id)
+}
+
+// what the attributes of an edge statement mean to Graphographic
+type dotEdgeSpec struct {
+	id, pair EdgeID
+	cost     int32
+	// the attributes that are kept under dot:
+	attrs Attributes
+}
+
+func dotReadEdge(attrs dotAttrs) (dotEdgeSpec, error) {
+	var spec dotEdgeSpec
+	understood := []string{"weight", "gg_cost", "gg_id", "gg_pair"}
+	if ref, ok := attrs["gg_id"]; ok {
+		spec.id = dotEdgeRef(ref.text)
+	} else if spec.id = dotEdgeRef(attrs["id"].text); spec.id != 0 {
+		understood = append(understood, "id")
+	}
+	spec.pair = dotEdgeRef(attrs["gg_pair"].text)
+	costSet := false
+	for _, key := range []string{"weight", "gg_cost"} {
+		value, ok := attrs[key]
+		if !ok {
+			continue
+		}
+		cost, err := dotCost(key, value.text)
+		if err != nil {
+			return spec, err
+		}
+		spec.cost, costSet = cost, true
+		break
+	}
+	// a label can be any text, a numeric one is the cost when there is no weight.
+	// Files written before gg_cost existed repeat the cost in the label
+	if label, ok := attrs["label"]; ok {
+		if cost, err := dotCost("label", label.text); err == nil && (!costSet || cost == spec.cost) {
+			spec.cost = cost
+			understood = append(understood, "label")
+		}
+	}
+	spec.attrs = dotUnknown(attrs, understood...)
+	return spec, nil
+}
+
+func dotCost(key, value string) (int32, error) {
+	f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0, fmt.Errorf("edge %s %q is not a number", key, value)
+	}
+	if f > math.MaxInt32 || f < math.MinInt32 {
+		return 0, fmt.Errorf("edge %s %q does not fit in a cost", key, value)
+	}
+	return int32(math.Round(f)), nil
+}
+
+// Graphviz positions are in points with the y axis pointing up
+func dotParsePos(pos string) (rl.Vector2, bool) {
+	parts := strings.Split(strings.TrimSuffix(strings.TrimSpace(pos), "!"), ",")
+	if len(parts) < 2 {
+		return rl.Vector2{}, false
+	}
+	x, errX := strconv.ParseFloat(parts[0], 32)
+	y, errY := strconv.ParseFloat(parts[1], 32)
+	if errX != nil || errY != nil {
+		return rl.Vector2{}, false
+	}
+	return rl.Vector2{X: float32(x), Y: -float32(y)}, true
+}
+
+// resolves the escape sequences Graphviz allows in labels
+func dotUnescape(label, nodeName string) string {
+	var sb strings.Builder
+	runes := []rune(label)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '\\' || i+1 == len(runes) {
+			sb.WriteRune(runes[i])
+			continue
+		}
+		i++
+		switch runes[i] {
+		case 'n', 'l', 'r':
+			sb.WriteRune('\n')
+		case 'N':
+			sb.WriteString(nodeName)
+		default:
+			sb.WriteRune(runes[i])
+		}
+	}
+	return sb.String()
+}
+
+func dotQuote(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			sb.WriteString(`\"`)
+		case '\\':
+			sb.WriteString(`\\`)
+		case '\n':
+			sb.WriteString(`\n`)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+// writes an attribute kept by ReadDOT, the value is as it was read so only
+// double quotes need escaping
+func dotRaw(s string) string {
+	if strings.HasPrefix(s, "<") && strings.HasSuffix(s, ">") {
+		return s
+	}
+	plain := s != ""
+	for i, r := range s {
+		if !(r == '_' || unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r))) {
+			plain = false
+		}
+	}
+	if plain {
+		return s
+	}
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+// the kept DOT attributes of attrs sorted by name, without the ones in skip
+func dotEntries(attrs Attributes, skip ...string) []string {
+	entries := make([]string, 0)
+	for k, v := range attrs {
+		name, ok := strings.CutPrefix(k, dotPrefix)
+		if !ok || slices.Contains(skip, name) {
+			continue
+		}
+		entries = append(entries, dotRaw(name)+"="+dotRaw(v))
+	}
+	sort.Strings(entries)
+	return entries
+}
+
+// Read a graph from a Graphviz DOT document
+func ReadDOT(r io.Reader) (Graph, error) {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return Graph{}, err
+	}
+	g := New()
+	p := dotParser{
+		lex:    dotLexer{src: []rune(string(src)), line: 1},
+		g:      &g,
+		nodes:  make(map[string]*Node),
+		placed: make(map[*Node]bool),
+	}
+	if err := p.advance(); err != nil {
+		return Graph{}, err
+	}
+	if err := p.parseGraph(); err != nil {
+		return Graph{}, err
+	}
+	resolvePairs(&g, p.pending)
+	unplaced := make([]*Node, 0)
+	for node := range g.Nodes() {
+		if !p.placed[node] {
+			unplaced = append(unplaced, node)
+		}
+	}
+	arrangeInCircle(unplaced)
+	return g, nil
+}
+
+// Write the graph as a Graphviz DOT document. When every edge is in an undirected
+// pair the graph is written as an undirected graph with one edge per pair,
+// otherwise every edge is written on its own.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	undirected := g.IsUndirected()
+	names := make(map[*Node]string, g.NodeCount())
+	if undirected {
+		fmt.Fprintln(bw, "graph {")
+	} else {
+		fmt.Fprintln(bw, "digraph {")
+	}
+	lastNode, lastEdge := g.LastIDs()
+	fmt.Fprintf(bw, "\tgg_last_node=%d;\n\tgg_last_edge=%d;\n", lastNode, lastEdge)
+	for _, entry := range dotEntries(g.Attrs) {
+		fmt.Fprintf(bw, "\t%s;\n", entry)
+	}
+	for node := range g.Nodes() {
+		names[node] = fmt.Sprintf("n%d", node.ID)
+		// written as 0 - y so the origin does not come out as -0
+		fmt.Fprintf(bw, "\t%s [label=%s, pos=\"%g,%g\"",
+			names[node], dotQuote(node.Content), node.Position.X, 0-node.Position.Y)
+		for _, entry := range dotEntries(node.Attrs, "label", "pos") {
+			fmt.Fprintf(bw, ", %s", entry)
+		}
+		fmt.Fprintln(bw, "];")
+	}
+	written := make(map[*Edge]bool, g.EdgeCount())
+	for edge := range g.Edges() {
+		if written[edge] {
+			continue
+		}
+		op, pair := "->", edge.Pair()
+		if undirected {
+			op = "--"
+			written[pair] = true
+		}
+		attrs := make([]string, 0)
+		if id, ok := edge.Attrs[dotPrefix+"id"]; ok {
+			attrs = append(attrs, "id="+dotRaw(id), fmt.Sprintf("gg_id=\"e%d\"", edge.ID))
+		} else {
+			attrs = append(attrs, fmt.Sprintf("id=\"e%d\"", edge.ID))
+		}
+		// an undirected loop pairs itself again when it is read
+		if pair != nil && !(undirected && pair == edge) {
+			attrs = append(attrs, fmt.Sprintf("gg_pair=\"e%d\"", pair.ID))
+		}
+		// Graphviz rejects negative weights
+		if edge.Cost >= 0 {
+			attrs = append(attrs, fmt.Sprintf("weight=%d", edge.Cost))
+		} else {
+			attrs = append(attrs, fmt.Sprintf("gg_cost=%d", edge.Cost))
+		}
+		attrs = append(attrs, dotEntries(edge.Attrs, "id", "weight", "gg_cost", "gg_id", "gg_pair")...)
+		fmt.Fprintf(bw, "\t%s %s %s [%s];\n", names[edge.Tail], op, names[edge.Head], strings.Join(attrs, ", "))
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
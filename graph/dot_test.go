@@ -0,0 +1,316 @@
+package graph
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestDOTKeepsUnknownAttributes(t *testing.T) {
+	src := `digraph G {
+		rankdir=LR
+		graph [bgcolor="light blue"]
+		node [shape=box]
+		subgraph cluster_0 { label="dropped"; a }
+		a [color=red, label=<<b>A</b>>, tooltip=<<i>tip</i>>]
+		a -> b [style=dashed, label="say \"hi\"", weight=3]
+	}`
+	g, err := ReadDOT(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	check := func(g *Graph) {
+		t.Helper()
+		if g.Attrs["dot:rankdir"] != "LR" || g.Attrs["dot:bgcolor"] != "light blue" || len(g.Attrs) != 2 {
+			t.Errorf("graph attributes %v", g.Attrs)
+		}
+		var a, b *Node
+		for n := range g.Nodes() {
+			switch n.Content {
+			case "<b>A</b>":
+				a = n
+			case "b":
+				b = n
+			}
+		}
+		if a == nil || b == nil {
+			t.Fatal("nodes are missing")
+		}
+		want := Attributes{"dot:shape": "box", "dot:color": "red", "dot:tooltip": "<<i>tip</i>>"}
+		if len(a.Attrs) != len(want) {
+			t.Errorf("node a attributes %v", a.Attrs)
+		}
+		for k, v := range want {
+			if a.Attrs[k] != v {
+				t.Errorf("node a attribute %s is %q, want %q", k, a.Attrs[k], v)
+			}
+		}
+		// b was mentioned after the node defaults
+		if b.Attrs["dot:shape"] != "box" || len(b.Attrs) != 1 {
+			t.Errorf("node b attributes %v", b.Attrs)
+		}
+		e := g.EdgeBetween(a, b)
+		if e == nil || e.Cost != 3 || e.Attrs["dot:style"] != "dashed" || e.Attrs["dot:label"] != `say "hi"` || len(e.Attrs) != 2 {
+			t.Errorf("edge a b loaded as %+v", e)
+		}
+	}
+	check(&g)
+	var buf bytes.Buffer
+	if err := g.WriteDOT(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := ReadDOT(&buf)
+	if err != nil {
+		t.Fatalf("%v in\n%s", err, buf.String())
+	}
+	check(&loaded)
+}
+
+// the edges of g as "tail->head=cost" sorted, nodes are named by their content
+func describeEdges(g *Graph) string {
+	edges := make([]string, 0)
+	for e := range g.Edges() {
+		edges = append(edges, fmt.Sprintf("%s->%s=%d", e.Tail.Content, e.Head.Content, e.Cost))
+	}
+	sort.Strings(edges)
+	return strings.Join(edges, ", ")
+}
+
+func describeNodes(g *Graph) string {
+	nodes := make([]string, 0)
+	for n := range g.Nodes() {
+		nodes = append(nodes, n.Content)
+	}
+	sort.Strings(nodes)
+	return strings.Join(nodes, ", ")
+}
+
+func TestReadDOT(t *testing.T) {
+	for _, test := range []struct {
+		name, src, nodes, edges string
+	}{
+		{"digraph", `digraph G { a -> b; b -> c }`,
+			"a, b, c", "a->b=0, b->c=0"},
+		{"undirected", `graph { a -- b; b -- b }`,
+			"a, b", "a->b=0, b->a=0, b->b=0"},
+		{"keywords", `strict DiGraph { Node [label=x]; A -> B }`,
+			"x, x", "x->x=0"},
+		{"subgraphs", `digraph { a -> { b c }; subgraph s { d -> e }; { f; g } -> h }`,
+			"a, b, c, d, e, f, g, h", "a->b=0, a->c=0, d->e=0, f->h=0, g->h=0"},
+		{"node defaults", `digraph { a; node [label=other]; b; c [label=C]; subgraph { node [label=inner] d } e }`,
+			"C, a, inner, other, other", ""},
+		{"edge defaults", `digraph { edge [weight=4]; a -> b; { edge [weight=9] c -> d } b -> c [weight=1] }`,
+			"a, b, c, d", "a->b=4, b->c=1, c->d=9"},
+		{"ports", `digraph { a:p1 -> b:p2:n; c:sw -> a }`,
+			"a, b, c", "a->b=0, c->a=0"},
+		{"edge chains", `digraph { a -> b -> c -> a [weight=2] }`,
+			"a, b, c", "a->b=2, b->c=2, c->a=2"},
+		{"strings", `digraph { "x y" -> <<b>h</b>>; "con" + "cat" -> "q\"uote"; "-1" -> 2.5 }`,
+			"-1, 2.5, <b>h</b>, concat, q\"uote, x y", "-1->2.5=0, concat->q\"uote=0, x y-><b>h</b>=0"},
+		{"labels", `digraph { a [label="line\nbreak"]; b [label="\N!"]; c [label=<<i>c</i>>] }`,
+			"<i>c</i>, b!, line\nbreak", ""},
+		{"comments", "# 1 \"file.dot\"\ndigraph { // c -> d\n a -> b /* e -> f\n g -> h */ }",
+			"a, b", "a->b=0"},
+		{"costs", `digraph { a -> b [weight=5, label=1]; b -> c [label="3.6"]; c -> a [label=text]; a -> c [label=-2] }`,
+			"a, b, c", "a->b=5, a->c=-2, b->c=4, c->a=0"},
+	} {
+		g, err := ReadDOT(strings.NewReader(test.src))
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if nodes := describeNodes(&g); nodes != test.nodes {
+			t.Errorf("%s: nodes %q, want %q", test.name, nodes, test.nodes)
+		}
+		if edges := describeEdges(&g); edges != test.edges {
+			t.Errorf("%s: edges %q, want %q", test.name, edges, test.edges)
+		}
+	}
+}
+
+func TestReadDOTErrors(t *testing.T) {
+	for _, test := range []struct {
+		src, err string
+	}{
+		{`graph { a -> b }`, "DOT line 1: '->' used in an undirected graph"},
+		{`digraph { a -- b }`, "DOT line 1: '--' used in a directed graph"},
+		{"digraph {\n a -> b [weight=heavy] }", "DOT line 2: edge weight \"heavy\" is not a number"},
+		{"digraph {\n \"a -> b }", "DOT line 2: unterminated string"},
+		{"digraph { a /* b", "DOT line 1: unterminated comment"},
+		{`digraph { a -> }`, "DOT line 1: expected a node id, got \"}\""},
+		{`digraph { a`, "DOT line 1: expected '}', got end of file"},
+		{`tree { }`, "DOT line 1: expected 'graph' or 'digraph', got \"tree\""},
+	} {
+		if _, err := ReadDOT(strings.NewReader(test.src)); err == nil || err.Error() != test.err {
+			t.Errorf("%q: got %v, want %q", test.src, err, test.err)
+		}
+	}
+}
+
+func TestDOTPosition(t *testing.T) {
+	g, err := ReadDOT(strings.NewReader(`digraph { a [pos="10,20"]; b [pos="-5.5,0!"] }`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]rl.Vector2{"a": {X: 10, Y: -20}, "b": {X: -5.5, Y: 0}}
+	for n := range g.Nodes() {
+		if n.Position != want[n.Content] {
+			t.Errorf("%s is at %v, want %v", n.Content, n.Position, want[n.Content])
+		}
+	}
+	var buf bytes.Buffer
+	if err := g.WriteDOT(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, pos := range []string{`pos="10,20"`, `pos="-5.5,0"`} {
+		if !strings.Contains(buf.String(), pos) {
+			t.Errorf("%s is missing from\n%s", pos, buf.String())
+		}
+	}
+}
+
+func TestDOTRoundTrip(t *testing.T) {
+	for _, undirected := range []bool{false, true} {
+		g := New()
+		nodes := make([]*Node, 3)
+		for i, name := range []string{"a", "b \"quoted\"", "c\nd"} {
+			n := NewNode()
+			n.Content = name
+			n.Position = rl.Vector2{X: float32(i * 10), Y: float32(-i)}
+			nodes[i] = g.AddNode(n)
+		}
+		g.AddEdge(nodes[0], nodes[1]).Cost = 3
+		g.AddEdge(nodes[1], nodes[2]).Cost = -4
+		if undirected {
+			for _, e := range slices.Collect(g.Edges()) {
+				reverse := g.AddEdge(e.Head, e.Tail)
+				reverse.Cost = e.Cost
+				g.PairEdges(e, reverse)
+			}
+		}
+		var buf bytes.Buffer
+		if err := g.WriteDOT(&buf); err != nil {
+			t.Fatal(err)
+		}
+		if header := map[bool]string{false: "digraph {", true: "graph {"}[undirected]; !strings.HasPrefix(buf.String(), header) {
+			t.Errorf("written as\n%s", buf.String())
+		}
+		loaded, err := ReadDOT(bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			t.Fatalf("%v in\n%s", err, buf.String())
+		}
+		if describeNodes(&loaded) != describeNodes(&g) || describeEdges(&loaded) != describeEdges(&g) {
+			t.Errorf("loaded %q %q from\n%s", describeNodes(&loaded), describeEdges(&loaded), buf.String())
+		}
+		for _, n := range nodes {
+			ln := loaded.NodeByID(n.ID)
+			if ln == nil || ln.Content != n.Content || ln.Position != n.Position {
+				t.Errorf("node %d loaded as %+v", n.ID, ln)
+			}
+		}
+		// an undirected pair is one DOT edge, only the written edge keeps its ID
+		for e := range g.Edges() {
+			le := loaded.EdgeBetween(loaded.NodeByID(e.Tail.ID), loaded.NodeByID(e.Head.ID))
+			if le == nil || le.Cost != e.Cost {
+				t.Fatalf("edge %d loaded as %+v", e.ID, le)
+			}
+			if !undirected && le.ID != e.ID {
+				t.Errorf("edge %d loaded with ID %d", e.ID, le.ID)
+			}
+		}
+	}
+}
+
+func TestDOTKeepsDirectedPairsApart(t *testing.T) {
+	src := `digraph { a -> b [label="go"]; b -> a [label="go"]; a -> a [id=self, label=<<b>stay</b>>] }`
+	g, err := ReadDOT(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		if g.IsUndirected() || g.EdgeCount() != 3 {
+			t.Fatalf("read %d edges, undirected %v", g.EdgeCount(), g.IsUndirected())
+		}
+		labels := make([]string, 0)
+		for e := range g.Edges() {
+			if e.Pair() != nil || e.Cost != 0 {
+				t.Errorf("edge %s->%s is paired or costs %d", e.Tail.Content, e.Head.Content, e.Cost)
+			}
+			labels = append(labels, e.Attrs["dot:label"])
+		}
+		sort.Strings(labels)
+		if strings.Join(labels, ", ") != "<<b>stay</b>>, go, go" {
+			t.Errorf("labels %q", labels)
+		}
+		if loop := g.EdgeBetween(g.NodeByID(1), g.NodeByID(1)); loop == nil || loop.Attrs["dot:id"] != "self" {
+			t.Errorf("the loop lost its id, %+v", loop)
+		}
+		var buf bytes.Buffer
+		if err := g.WriteDOT(&buf); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(buf.String(), "digraph {") || strings.Contains(buf.String(), "--") {
+			t.Fatalf("written as\n%s", buf.String())
+		}
+		if g, err = ReadDOT(&buf); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDOTKeepsPairs(t *testing.T) {
+	// one undirected pair among directed edges, the directed ones go both ways too
+	g := New()
+	a := g.AddNode(NewNode())
+	b := g.AddNode(NewNode())
+	c := g.AddNode(NewNode())
+	ab, ba := g.AddEdge(a, b), g.AddEdge(b, a)
+	g.PairEdges(ab, ba)
+	ab.Cost, ba.Cost = -7, -7
+	bc, cb := g.AddEdge(b, c), g.AddEdge(c, b)
+	var buf bytes.Buffer
+	if err := g.WriteDOT(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := ReadDOT(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("%v in\n%s", err, buf.String())
+	}
+	if pair := loaded.EdgeByID(ab.ID).Pair(); pair == nil || pair.ID != ba.ID {
+		t.Errorf("edge %d is paired with %v in\n%s", ab.ID, pair, buf.String())
+	}
+	if loaded.EdgeByID(ab.ID).Cost != -7 || loaded.EdgeByID(ba.ID).Cost != -7 {
+		t.Errorf("the negative cost was lost in\n%s", buf.String())
+	}
+	if loaded.EdgeByID(bc.ID).Pair() != nil || loaded.EdgeByID(cb.ID).Pair() != nil {
+		t.Errorf("directed edges were paired in\n%s", buf.String())
+	}
+}
+
+func TestDOTEscapes(t *testing.T) {
+	g := New()
+	contents := []string{`back\`, `\"`, `a\b"c`, `\\N`, "two\nlines"}
+	for _, content := range contents {
+		n := NewNode()
+		n.Content = content
+		g.AddNode(n)
+	}
+	var buf bytes.Buffer
+	if err := g.WriteDOT(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := ReadDOT(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("%v in\n%s", err, buf.String())
+	}
+	for n := range g.Nodes() {
+		if ln := loaded.NodeByID(n.ID); ln == nil || ln.Content != n.Content {
+			t.Errorf("%q loaded as %+v from\n%s", n.Content, ln, buf.String())
+		}
+	}
+}
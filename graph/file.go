@@ -0,0 +1,63 @@
+package graph
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Save the graph to a file at path, the format is picked by the file extension.
+// The graph is written to a temporary file next to path first and only moved over
+// it when writing succeeded, so a failed save leaves the old file intact
+func (g *Graph) SaveFile(path string) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return err
+	}
+	// the temporary file is private, keep the mode of the file being replaced
+	mode := os.FileMode(0o644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	var write func(io.Writer) error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".dot", ".gv":
+		write = g.WriteDOT
+	case ".graphml":
+		write = g.WriteGraphML
+	default:
+		write = g.WriteJSON
+	}
+	err = write(f)
+	if err == nil {
+		err = f.Chmod(mode)
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
+
+// Load a graph from a file at path, the format is picked by the file extension
+func LoadFile(path string) (Graph, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Graph{}, err
+	}
+	defer f.Close()
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".dot", ".gv":
+		return ReadDOT(f)
+	case ".graphml":
+		return ReadGraphML(f)
+	default:
+		return ReadJSON(f)
+	}
+}
@@ -0,0 +1,151 @@
+package graph
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveFileKeepsOldFileOnError(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "graph.json")
+	g := New()
+	a := g.AddNode(NewNode())
+	a.Content = "a"
+	if err := g.SaveFile(path); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a position JSON can not encode makes the write fail halfway
+	a.Position.X = float32(math.NaN())
+	if err := g.SaveFile(path); err == nil {
+		t.Fatal("saving a NaN position did not fail")
+	}
+	after, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(after) != string(saved) {
+		t.Error("a failed save changed the file")
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("the temporary file was left behind, %d files in the directory", len(entries))
+	}
+}
+
+func TestSaveFilePicksFormat(t *testing.T) {
+	g := New()
+	a := g.AddNode(NewNode())
+	b := g.AddNode(NewNode())
+	g.AddEdge(a, b).Cost = 3
+	for _, name := range []string{"g.json", "g.dot", "g.gv", "g.graphml"} {
+		path := filepath.Join(t.TempDir(), name)
+		if err := g.SaveFile(path); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		loaded, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if loaded.NodeCount() != 2 || loaded.EdgeCount() != 1 {
+			t.Errorf("%s: loaded %d nodes and %d edges", name, loaded.NodeCount(), loaded.EdgeCount())
+		}
+	}
+}
+
+// six nodes each linked to the next two, with a node, its edges and a few
+// other edges removed, the last node and edge included
+func idTestGraph(undirected bool) Graph {
+	g := New()
+	nodes := make([]*Node, 6)
+	for i := range nodes {
+		nodes[i] = g.AddNode(NewNode())
+		nodes[i].Content = string(rune('a' + i))
+	}
+	for i := range nodes {
+		for step := 1; step <= 2; step++ {
+			e := g.AddEdge(nodes[i], nodes[(i+step)%len(nodes)])
+			e.Cost = int32(10*step + i)
+			if undirected {
+				opp := g.AddEdge(e.Head, e.Tail)
+				opp.Cost = e.Cost
+				g.PairEdges(e, opp)
+			}
+		}
+	}
+	g.RemoveNode(nodes[1])
+	g.RemoveNode(nodes[5])
+	e := g.EdgeBetween(nodes[3], nodes[4])
+	if undirected {
+		g.RemoveEdge(e.Pair())
+	}
+	g.RemoveEdge(e)
+	return g
+}
+
+// every format keeps node and edge IDs, also with the gaps deletions leave
+func TestFormatsKeepIDs(t *testing.T) {
+	for _, undirected := range []bool{false, true} {
+		g := idTestGraph(undirected)
+		lastNode, lastEdge := g.LastIDs()
+		for _, format := range []struct {
+			name  string
+			write func(*Graph, io.Writer) error
+			read  func(io.Reader) (Graph, error)
+		}{
+			{"JSON", (*Graph).WriteJSON, ReadJSON},
+			{"DOT", (*Graph).WriteDOT, ReadDOT},
+			{"GraphML", (*Graph).WriteGraphML, ReadGraphML},
+		} {
+			name := format.name
+			if undirected {
+				name += " undirected"
+			}
+			var buf bytes.Buffer
+			if err := format.write(&g, &buf); err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			loaded, err := format.read(&buf)
+			if err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			if loaded.NodeCount() != g.NodeCount() || loaded.EdgeCount() != g.EdgeCount() {
+				t.Errorf("%s: loaded %d nodes and %d edges", name, loaded.NodeCount(), loaded.EdgeCount())
+			}
+			for n := range g.Nodes() {
+				if ln := loaded.NodeByID(n.ID); ln == nil || ln.Content != n.Content {
+					t.Errorf("%s: node %d loaded as %+v", name, n.ID, ln)
+				}
+			}
+			for e := range g.Edges() {
+				le := loaded.EdgeByID(e.ID)
+				if le == nil || le.Tail.ID != e.Tail.ID || le.Head.ID != e.Head.ID || le.Cost != e.Cost {
+					t.Errorf("%s: edge %d loaded as %+v", name, e.ID, le)
+					continue
+				}
+				if (le.Pair() == nil) != (e.Pair() == nil) || le.Pair() != nil && le.Pair().ID != e.Pair().ID {
+					t.Errorf("%s: edge %d loaded with pair %v", name, e.ID, le.Pair())
+				}
+			}
+			if loaded.IsUndirected() != undirected {
+				t.Errorf("%s: loaded as undirected %v", name, loaded.IsUndirected())
+			}
+			// freed IDs are not handed out again
+			n := loaded.AddNode(NewNode())
+			e := loaded.AddEdge(n, n)
+			if n.ID <= lastNode || e.ID <= lastEdge {
+				t.Errorf("%s: new node %d or edge %d reused an ID up to %d, %d", name, n.ID, e.ID, lastNode, lastEdge)
+			}
+		}
+	}
+}
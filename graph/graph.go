@@ -1,7 +1,8 @@
 package graph
 
 import (
-	"container/list"
+	"iter"
+	"math"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
@@ -10,109 +11,267 @@ type AlgoData struct {
 	Explored bool
 	Highlighted bool
 	Tag string
+	// set by algorithms that split the graph into parts or colour it, each group is
+	// drawn in its own colour, 0 means no group
+	Group int
 	// extra data that could be assigned and used by an algorithm
 	Custom any
 }
 
+// attributes read from a file that Graphographic has no use for, kept so that saving
+// the graph again does not drop them. Keys are prefixed with the format they came from
+type Attributes map[string]string
+
+// identifiers are assigned by the graph and never reused while it exists, 0 means unassigned
+type NodeID uint64
+type EdgeID uint64
+
 type Node struct {
+	ID NodeID
 	Position rl.Vector2
 	Content string
-	Edges *list.List
 	// saved from draw pass
 	Radius float32
 	Data AlgoData
+	Attrs Attributes
+	// edges where the node is the tail and where it is the head
+	out []*Edge
+	in []*Edge
+	// position in Graph.nodes
+	index int
 }
 
 type Edge struct {
+	ID EdgeID
 	Tail *Node
 	Head *Node
 	Cost int32
 	// set after each draw pass so it does not have to be recalculated
 	StartPos, EndPos rl.Vector2
 	Data AlgoData
+	Attrs Attributes
+	// the other edge of an undirected pair, see PairEdges
+	pair *Edge
+	// positions in Graph.edges, Tail.out and Head.in
+	index, outIndex, inIndex int
 }
 
+type nodePair struct {
+	tail, head NodeID
+}
+
+// Nodes and edges live in dense slices so iterating them is cheap, removal swaps the
+// last element into the hole. The maps give constant time lookups by ID and by endpoints
 type Graph struct {
-	Nodes *list.List
-	Edges *list.List
+	Attrs Attributes
+	nodes []*Node
+	edges []*Edge
+	nodeByID map[NodeID]*Node
+	edgeByID map[EdgeID]*Edge
+	// edges from tail to head, more than one when the file had parallel edges
+	between map[nodePair][]*Edge
+	// highest identifiers handed out so far
+	lastNodeID NodeID
+	lastEdgeID EdgeID
 }
 
 func New() Graph {
 	return Graph{
-		Nodes: list.New(),
-		Edges: list.New(),
+		nodeByID: make(map[NodeID]*Node),
+		edgeByID: make(map[EdgeID]*Edge),
+		between: make(map[nodePair][]*Edge),
+	}
+}
+
+// the zero Graph is usable too
+func (g *Graph) lazyInit() {
+	if g.nodeByID == nil {
+		*g = Graph{
+			Attrs: g.Attrs,
+			nodeByID: make(map[NodeID]*Node),
+			edgeByID: make(map[EdgeID]*Edge),
+			between: make(map[nodePair][]*Edge),
+		}
 	}
 }
 
 // Connect two nodes with an edge, get the pointer to the edge
 func (g *Graph) AddEdge(a, b *Node) *Edge {
+	return g.AddEdgeID(0, a, b)
+}
+
+// Like AddEdge but the edge keeps id when it is not taken, used to restore removed edges
+func (g *Graph) AddEdgeID(id EdgeID, a, b *Node) *Edge {
+	g.lazyInit()
+	if _, taken := g.edgeByID[id]; id == 0 || taken {
+		g.lastEdgeID++
+		id = g.lastEdgeID
+	} else if id > g.lastEdgeID {
+		g.lastEdgeID = id
+	}
 	aToB := &Edge{
+		ID: id,
 		Tail: a,
 		Head: b,
+		index: len(g.edges),
+		outIndex: len(a.out),
+		inIndex: len(b.in),
 	}
-	g.Edges.PushBack(aToB);
-	a.Edges.PushBack(aToB);
-	b.Edges.PushBack(aToB);
+	g.edges = append(g.edges, aToB)
+	a.out = append(a.out, aToB)
+	b.in = append(b.in, aToB)
+	g.edgeByID[id] = aToB
+	pair := nodePair{a.ID, b.ID}
+	g.between[pair] = append(g.between[pair], aToB)
 	return aToB
 }
 
-func (node *Node) removeEdgeToNode(other *Node) {
-	edgesToRemove := make([]*list.Element, 0)
-	for edgeIt := node.Edges.Front(); edgeIt != nil; edgeIt = edgeIt.Next() {
-		edge := edgeIt.Value.(*Edge)
-		if edge.Tail == other || edge.Head == other {
-			edgesToRemove = append(edgesToRemove, edgeIt)
-		}
+func (g *Graph) RemoveNode(n *Node) {
+	if g.nodeByID[n.ID] != n {
+		return
 	}
-	for _, e := range edgesToRemove {
-		node.Edges.Remove(e)
+	for len(n.out) > 0 {
+		g.RemoveEdge(n.out[len(n.out)-1])
 	}
+	for len(n.in) > 0 {
+		g.RemoveEdge(n.in[len(n.in)-1])
+	}
+	last := g.nodes[len(g.nodes)-1]
+	g.nodes[n.index] = last
+	last.index = n.index
+	g.nodes = g.nodes[:len(g.nodes)-1]
+	delete(g.nodeByID, n.ID)
 }
 
-func (node *Node) removeEdge(e *Edge) {
-	for edgeIt := node.Edges.Front(); edgeIt != nil; edgeIt = edgeIt.Next() {
-		edge := edgeIt.Value.(*Edge)
-		if edge == e {
-			node.Edges.Remove(edgeIt)
-		}
+// Add a copy of n without any edges, it keeps n.ID when that is set and not taken
+func (g *Graph) AddNode(n Node) *Node {
+	g.lazyInit()
+	if _, taken := g.nodeByID[n.ID]; n.ID == 0 || taken {
+		g.lastNodeID++
+		n.ID = g.lastNodeID
+	} else if n.ID > g.lastNodeID {
+		g.lastNodeID = n.ID
 	}
+	n.out = nil
+	n.in = nil
+	n.index = len(g.nodes)
+	nPtr := &n
+	g.nodes = append(g.nodes, nPtr)
+	g.nodeByID[n.ID] = nPtr
+	return nPtr
 }
 
-func (g *Graph) RemoveNode(n *Node) {
-	edgesToRemove := make([]*list.Element, 0)
-	for edgeIt := g.Edges.Front(); edgeIt != nil; edgeIt = edgeIt.Next() {
-		edge := edgeIt.Value.(*Edge)
-		if edge.Tail == n || edge.Head == n {
-			edgesToRemove = append(edgesToRemove, edgeIt)
-			edge.Tail.removeEdgeToNode(n)
-			edge.Head.removeEdgeToNode(n)
+func (g *Graph) RemoveEdge(e *Edge) {
+	if g.edgeByID[e.ID] != e {
+		return
+	}
+	last := g.edges[len(g.edges)-1]
+	g.edges[e.index] = last
+	last.index = e.index
+	g.edges = g.edges[:len(g.edges)-1]
+
+	out := e.Tail.out
+	last = out[len(out)-1]
+	out[e.outIndex] = last
+	last.outIndex = e.outIndex
+	e.Tail.out = out[:len(out)-1]
+
+	in := e.Head.in
+	last = in[len(in)-1]
+	in[e.inIndex] = last
+	last.inIndex = e.inIndex
+	e.Head.in = in[:len(in)-1]
+
+	delete(g.edgeByID, e.ID)
+	if e.pair != nil {
+		e.pair.pair = nil
+		e.pair = nil
+	}
+	pair := nodePair{e.Tail.ID, e.Head.ID}
+	parallel := g.between[pair]
+	for i, other := range parallel {
+		if other == e {
+			parallel = append(parallel[:i], parallel[i+1:]...)
+			break
 		}
 	}
-	for _, e := range edgesToRemove {
-		g.Edges.Remove(e)
+	if len(parallel) == 0 {
+		delete(g.between, pair)
+	} else {
+		g.between[pair] = parallel
+	}
+}
+
+func (g *Graph) NodeByID(id NodeID) *Node {
+	return g.nodeByID[id]
+}
+
+func (g *Graph) EdgeByID(id EdgeID) *Edge {
+	return g.edgeByID[id]
+}
+
+// Get an edge going from tail to head or nil if there is none
+func (g *Graph) EdgeBetween(tail, head *Node) *Edge {
+	if parallel := g.between[nodePair{tail.ID, head.ID}]; len(parallel) > 0 {
+		return parallel[0]
+	}
+	return nil
+}
+
+func (g *Graph) NodeCount() int {
+	return len(g.nodes)
+}
+
+func (g *Graph) EdgeCount() int {
+	return len(g.edges)
+}
+
+// Iterate over all nodes. Nodes must not be added or removed while iterating
+func (g *Graph) Nodes() iter.Seq[*Node] {
+	return func(yield func(*Node) bool) {
+		for _, node := range g.nodes {
+			if !yield(node) {
+				return
+			}
+		}
 	}
+}
 
-	for nodeIt := g.Nodes.Front(); nodeIt != nil; nodeIt = nodeIt.Next() {
-		node := nodeIt.Value.(*Node)
-		if node == n {
-			g.Nodes.Remove(nodeIt)
-			break;
+// Iterate over all edges. Edges must not be added or removed while iterating
+func (g *Graph) Edges() iter.Seq[*Edge] {
+	return func(yield func(*Edge) bool) {
+		for _, edge := range g.edges {
+			if !yield(edge) {
+				return
+			}
 		}
 	}
 }
-func (g *Graph) AddNode(n Node) *Node {
-	nPtr := &n
-	g.Nodes.PushBack(nPtr)
-	return nPtr
+
+// Iterate over the edges leaving the node
+func (n *Node) Out() iter.Seq[*Edge] {
+	return edgeSeq(n.out)
 }
-func (g *Graph) RemoveEdge(e *Edge) {
-	for edgeIt := g.Edges.Front(); edgeIt != nil; edgeIt = edgeIt.Next() {
-		edge := edgeIt.Value.(*Edge)
-		if edge == e {
-			edge.Head.removeEdge(edge)
-			edge.Tail.removeEdge(edge)
-			g.Edges.Remove(edgeIt)
-			break
+
+// Iterate over the edges entering the node
+func (n *Node) In() iter.Seq[*Edge] {
+	return edgeSeq(n.in)
+}
+
+func (n *Node) OutDegree() int {
+	return len(n.out)
+}
+
+func (n *Node) InDegree() int {
+	return len(n.in)
+}
+
+func edgeSeq(edges []*Edge) iter.Seq[*Edge] {
+	return func(yield func(*Edge) bool) {
+		for _, edge := range edges {
+			if !yield(edge) {
+				return
+			}
 		}
 	}
 }
@@ -121,17 +280,81 @@ func NewNode() Node {
 	return Node {
 		Position: rl.Vector2Zero(),
 		Content: "",
-		Edges: list.New(),
 	}
 }
 
-func (n *Node) IsConnectedTo(b *Node) bool {
-	for edgeIt := n.Edges.Front(); edgeIt != nil; edgeIt = edgeIt.Next() {
-		edge := edgeIt.Value.(*Edge)
-		if edge.Head == b {
-			return true
+// Get the other edge of the undirected pair the edge is in, nil for a directed edge.
+// A loop that is undirected is its own pair
+func (e *Edge) Pair() *Edge {
+	return e.pair
+}
+
+// Make a and b an undirected pair like the edges created in UNDIRECTED connect mode,
+// they have to connect the same nodes in opposite directions. Pairs a or b were in
+// before are dissolved. False when the edges can not be paired
+func (g *Graph) PairEdges(a, b *Edge) bool {
+	if g.edgeByID[a.ID] != a || g.edgeByID[b.ID] != b || a.Tail != b.Head || a.Head != b.Tail {
+		return false
+	}
+	for _, e := range []*Edge{a, b} {
+		if e.pair != nil {
+			e.pair.pair = nil
+		}
+	}
+	a.pair, b.pair = b, a
+	return true
+}
+
+// true when the graph has edges and every one of them is in an undirected pair, like
+// when all edges were created in UNDIRECTED connect mode
+func (g *Graph) IsUndirected() bool {
+	for _, edge := range g.edges {
+		if edge.pair == nil {
+			return false
 		}
 	}
-	return false
+	return len(g.edges) > 0
+}
+
+// The highest node and edge IDs handed out so far, saved so that IDs freed by
+// deletions are not handed out again after the graph is loaded
+func (g *Graph) LastIDs() (NodeID, EdgeID) {
+	return g.lastNodeID, g.lastEdgeID
+}
+
+// Raise the highest IDs handed out to at least node and edge, see LastIDs
+func (g *Graph) ReserveIDs(node NodeID, edge EdgeID) {
+	g.lazyInit()
+	g.lastNodeID = max(g.lastNodeID, node)
+	g.lastEdgeID = max(g.lastEdgeID, edge)
+}
 
+// place the nodes evenly on a circle around the origin
+func arrangeInCircle(nodes []*Node) {
+	if len(nodes) < 2 {
+		return
+	}
+	radius := math.Max(200, float64(len(nodes))*80/(2*math.Pi))
+	for i, node := range nodes {
+		angle := 2 * math.Pi * float64(i) / float64(len(nodes))
+		node.Position = rl.Vector2{
+			X: float32(math.Round(radius * math.Cos(angle))),
+			Y: float32(math.Round(radius * math.Sin(angle))),
+		}
+	}
+}
+
+// an edge read from a file and the ID of its pair, which may come later in the file
+type pendingPair struct {
+	edge *Edge
+	pair EdgeID
+}
+
+// pairs the edges once the whole file is read, pairs that do not fit are dropped
+func resolvePairs(g *Graph, pending []pendingPair) {
+	for _, p := range pending {
+		if pair := g.EdgeByID(p.pair); pair != nil && pair.Pair() == nil && p.edge.Pair() == nil {
+			g.PairEdges(p.edge, pair)
+		}
+	}
 }
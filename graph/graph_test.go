@@ -0,0 +1,183 @@
+package graph
+
+import (
+	"math/rand"
+	"testing"
+)
+
+const benchEdges = 100_000
+
+// a random graph with benchEdges edges over benchEdges/10 nodes
+func benchGraph(b *testing.B) (Graph, []*Node) {
+	b.Helper()
+	rng := rand.New(rand.NewSource(1))
+	g := New()
+	nodes := make([]*Node, benchEdges/10)
+	for i := range nodes {
+		nodes[i] = g.AddNode(NewNode())
+	}
+	for g.EdgeCount() < benchEdges {
+		tail, head := nodes[rng.Intn(len(nodes))], nodes[rng.Intn(len(nodes))]
+		if g.EdgeBetween(tail, head) == nil {
+			g.AddEdge(tail, head)
+		}
+	}
+	return g, nodes
+}
+
+func TestRemoveKeepsIndicesConsistent(t *testing.T) {
+	g := New()
+	a := g.AddNode(NewNode())
+	b := g.AddNode(NewNode())
+	c := g.AddNode(NewNode())
+	ab := g.AddEdge(a, b)
+	g.AddEdge(b, c)
+	ca := g.AddEdge(c, a)
+	g.AddEdge(a, c)
+
+	g.RemoveEdge(ab)
+	if g.EdgeBetween(a, b) != nil || g.EdgeByID(ab.ID) != nil {
+		t.Fatal("removed edge can still be found")
+	}
+	g.RemoveNode(b)
+	if g.NodeCount() != 2 || g.EdgeCount() != 2 {
+		t.Fatalf("got %d nodes and %d edges, want 2 and 2", g.NodeCount(), g.EdgeCount())
+	}
+	if g.EdgeBetween(c, a) != ca || a.InDegree() != 1 || a.OutDegree() != 1 {
+		t.Fatal("edges between the remaining nodes were disturbed")
+	}
+	for node := range g.Nodes() {
+		if g.nodes[node.index] != node {
+			t.Fatalf("node %d has a stale index", node.ID)
+		}
+	}
+	for edge := range g.Edges() {
+		if g.edges[edge.index] != edge || edge.Tail.out[edge.outIndex] != edge || edge.Head.in[edge.inIndex] != edge {
+			t.Fatalf("edge %d has a stale index", edge.ID)
+		}
+	}
+	if d := g.AddNode(NewNode()); d.ID != 4 {
+		t.Fatalf("new node got id %d, ids must not be reused", d.ID)
+	}
+}
+
+// what the history does to undo a deletion, the node and its edges come back with their ids
+func TestRestoreRemovedNode(t *testing.T) {
+	g := New()
+	a := g.AddNode(NewNode())
+	b := g.AddNode(NewNode())
+	ab := g.AddEdge(a, b)
+	ba := g.AddEdge(b, a)
+	snapshot := *b
+	g.RemoveNode(b)
+	if a.OutDegree() != 0 || a.InDegree() != 0 {
+		t.Fatal("edges of the removed node are still attached to its neighbour")
+	}
+
+	restored := g.AddNode(snapshot)
+	if restored.ID != b.ID || restored.OutDegree() != 0 {
+		t.Fatalf("restored node got id %d and %d edges, want %d and none", restored.ID, restored.OutDegree(), b.ID)
+	}
+	if e := g.AddEdgeID(ab.ID, a, restored); e.ID != ab.ID || g.EdgeBetween(a, restored) != e {
+		t.Fatal("edge from a was not restored with its id")
+	}
+	if e := g.AddEdgeID(ba.ID, restored, a); e.ID != ba.ID || g.EdgeBetween(restored, a) != e {
+		t.Fatal("edge to a was not restored with its id")
+	}
+	if e := g.AddEdgeID(ab.ID, a, restored); e.ID == ab.ID {
+		t.Fatal("an id that is taken must not be handed out again")
+	}
+}
+
+func BenchmarkBuild(b *testing.B) {
+	for b.Loop() {
+		benchGraph(b)
+	}
+}
+
+func BenchmarkEdgeBetween(b *testing.B) {
+	g, nodes := benchGraph(b)
+	rng := rand.New(rand.NewSource(2))
+	for b.Loop() {
+		g.EdgeBetween(nodes[rng.Intn(len(nodes))], nodes[rng.Intn(len(nodes))])
+	}
+}
+
+func BenchmarkEdgeByID(b *testing.B) {
+	g, _ := benchGraph(b)
+	rng := rand.New(rand.NewSource(2))
+	for b.Loop() {
+		g.EdgeByID(EdgeID(rng.Intn(benchEdges) + 1))
+	}
+}
+
+func BenchmarkIterateAdjacency(b *testing.B) {
+	g, _ := benchGraph(b)
+	for b.Loop() {
+		var cost int32
+		for node := range g.Nodes() {
+			for edge := range node.Out() {
+				cost += edge.Cost
+			}
+		}
+	}
+}
+
+// removes every node, and with them all the edges
+func BenchmarkRemoveNode(b *testing.B) {
+	for b.Loop() {
+		b.StopTimer()
+		g, nodes := benchGraph(b)
+		b.StartTimer()
+		for _, node := range nodes {
+			g.RemoveNode(node)
+		}
+	}
+}
+
+func BenchmarkRemoveEdge(b *testing.B) {
+	for b.Loop() {
+		b.StopTimer()
+		g, _ := benchGraph(b)
+		edges := make([]*Edge, 0, benchEdges)
+		for edge := range g.Edges() {
+			edges = append(edges, edge)
+		}
+		b.StartTimer()
+		for _, edge := range edges {
+			g.RemoveEdge(edge)
+		}
+	}
+}
+
+func TestPairEdges(t *testing.T) {
+	g := New()
+	a := g.AddNode(NewNode())
+	b := g.AddNode(NewNode())
+	ab, ba := g.AddEdge(a, b), g.AddEdge(b, a)
+	if g.IsUndirected() {
+		t.Fatal("a graph without pairs is undirected")
+	}
+	if g.PairEdges(ab, ab) || ab.Pair() != nil {
+		t.Fatal("an edge that is not a loop was paired with itself")
+	}
+	if !g.PairEdges(ab, ba) || ab.Pair() != ba || ba.Pair() != ab || !g.IsUndirected() {
+		t.Fatal("the edges were not paired")
+	}
+	// a second edge the other way takes over the pair
+	ba2 := g.AddEdge(b, a)
+	if !g.PairEdges(ab, ba2) || ba.Pair() != nil || ab.Pair() != ba2 {
+		t.Fatal("the old pair was not dissolved")
+	}
+	g.RemoveEdge(ba2)
+	if ab.Pair() != nil {
+		t.Fatal("removing an edge left its pair paired")
+	}
+	loop := g.AddEdge(a, a)
+	if !g.PairEdges(loop, loop) || loop.Pair() != loop {
+		t.Fatal("a loop can not be its own pair")
+	}
+	if g.PairEdges(ab, loop) {
+		t.Fatal("edges not going opposite ways were paired")
+	}
+}
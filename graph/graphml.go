@@ -0,0 +1,495 @@
+package graph
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// GraphML support. Data Graphographic understands (labels, positions and
+// weights, recognised by the key's attr.name) is mapped onto the graph, every
+// other <data> element is stored verbatim in the Attrs of its owner under
+// "graphml:<key id>". The <key> definitions those entries need are kept in
+// Graph.Attrs under "graphml:key:<key id>" and namespace declarations of the
+// document under "graphml:xmlns:<prefix>", so yEd or Gephi specific data
+// survives a load and save. Only a graph made of undirected pairs is written as
+// an undirected document, with one <edge> per pair. The pair of an edge is kept
+// in the gg_pair data.
+
+const (
+	graphmlNamespace   = "http://graphml.graphdrawing.org/xmlns"
+	graphmlPrefix      = "graphml:"
+	graphmlKeyPrefix   = "graphml:key:"
+	graphmlXmlnsPrefix = "graphml:xmlns:"
+	// data that belongs to the <graphml> element rather than the <graph>
+	graphmlDocPrefix = "graphml:doc:"
+)
+
+// keys written for the data Graphographic understands
+const (
+	graphmlLabelKey = "gg_label"
+	graphmlXKey     = "gg_x"
+	graphmlYKey     = "gg_y"
+	graphmlCostKey  = "gg_cost"
+	graphmlPairKey  = "gg_pair"
+	// the highest IDs handed out, see Graph.LastIDs
+	graphmlLastNodeKey = "gg_last_node"
+	graphmlLastEdgeKey = "gg_last_edge"
+)
+
+type graphmlData struct {
+	Key   string `xml:"key,attr"`
+	Text  string `xml:",chardata"`
+	Inner string `xml:",innerxml"`
+}
+
+type graphmlKey struct {
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Default *struct {
+		Text string `xml:",chardata"`
+	} `xml:"default"`
+	Inner string `xml:",innerxml"`
+}
+
+func (k *graphmlKey) attr(name string) string {
+	for _, a := range k.Attrs {
+		if a.Name.Space == "" && a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// the raw element so it can be written back unchanged
+func (k *graphmlKey) raw() string {
+	var sb strings.Builder
+	sb.WriteString("<key")
+	for _, a := range k.Attrs {
+		if a.Name.Space != "" {
+			continue
+		}
+		fmt.Fprintf(&sb, " %s=\"%s\"", a.Name.Local, graphmlEscape(a.Value))
+	}
+	if strings.TrimSpace(k.Inner) == "" {
+		sb.WriteString("/>")
+	} else {
+		sb.WriteString(">" + k.Inner + "</key>")
+	}
+	return sb.String()
+}
+
+type graphmlNode struct {
+	ID     string         `xml:"id,attr"`
+	Data   []graphmlData  `xml:"data"`
+	Graphs []graphmlGraph `xml:"graph"`
+}
+
+type graphmlEdge struct {
+	ID       string        `xml:"id,attr"`
+	Source   string        `xml:"source,attr"`
+	Target   string        `xml:"target,attr"`
+	Directed string        `xml:"directed,attr"`
+	Data     []graphmlData `xml:"data"`
+}
+
+type graphmlGraph struct {
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Data        []graphmlData `xml:"data"`
+	Nodes       []graphmlNode `xml:"node"`
+	Edges       []graphmlEdge `xml:"edge"`
+}
+
+type graphmlDocument struct {
+	XMLName xml.Name       `xml:"graphml"`
+	Attrs   []xml.Attr     `xml:",any,attr"`
+	Keys    []graphmlKey   `xml:"key"`
+	Data    []graphmlData  `xml:"data"`
+	Graphs  []graphmlGraph `xml:"graph"`
+}
+
+// what a key means to Graphographic
+type graphmlRole int
+
+const (
+	graphmlUnknown graphmlRole = iota
+	graphmlLabel
+	graphmlName
+	graphmlX
+	graphmlY
+	graphmlCost
+	graphmlPair
+	graphmlLastNode
+	graphmlLastEdge
+)
+
+type graphmlReader struct {
+	g     *Graph
+	keys  map[string]*graphmlKey
+	roles map[string]graphmlRole
+	nodes map[string]*Node
+	// key ids used by some data element, only their definitions are kept
+	used map[string]bool
+	// nodes without a position
+	unplaced []*Node
+	// key ids in document order, the first default of a role wins
+	keyOrder []string
+	// directed edges and the ID of their pair, paired once every edge is read
+	pending []pendingPair
+}
+
+func graphmlKeyRole(key *graphmlKey) graphmlRole {
+	name := strings.ToLower(key.attr("attr.name"))
+	switch key.attr("for") {
+	case "node":
+		switch name {
+		case "label":
+			return graphmlLabel
+		case "name":
+			return graphmlName
+		case "x":
+			return graphmlX
+		case "y":
+			return graphmlY
+		}
+	case "edge":
+		switch name {
+		case "weight", "cost", "label":
+			return graphmlCost
+		case graphmlPairKey:
+			return graphmlPair
+		}
+	case "graph":
+		switch name {
+		case graphmlLastNodeKey:
+			return graphmlLastNode
+		case graphmlLastEdgeKey:
+			return graphmlLastEdge
+		}
+	}
+	return graphmlUnknown
+}
+
+// value of the data for key in data, falling back to the key's default
+func (r *graphmlReader) value(data []graphmlData, role graphmlRole) (string, bool) {
+	for _, d := range data {
+		if r.roles[d.Key] == role {
+			return d.Text, true
+		}
+	}
+	for _, id := range r.keyOrder {
+		if key := r.keys[id]; r.roles[id] == role && key.Default != nil {
+			return key.Default.Text, true
+		}
+	}
+	return "", false
+}
+
+// data the reader did not map onto the graph goes into attrs
+func (r *graphmlReader) passthrough(data []graphmlData, prefix string, understood func(graphmlData) bool) Attributes {
+	var attrs Attributes
+	for _, d := range data {
+		if understood(d) {
+			continue
+		}
+		if attrs == nil {
+			attrs = make(Attributes)
+		}
+		attrs[prefix+d.Key] = d.Inner
+		r.used[d.Key] = true
+	}
+	return attrs
+}
+
+// nested graphs are flattened into the one graph Graphographic has, all nodes
+// are read before any edge since an edge may refer to a node of another graph
+func (r *graphmlReader) readNodes(gml *graphmlGraph) error {
+	for _, d := range gml.Data {
+		last, err := strconv.ParseUint(strings.TrimSpace(d.Text), 10, 64)
+		switch {
+		case err != nil:
+		case r.roles[d.Key] == graphmlLastNode:
+			r.g.ReserveIDs(NodeID(last), 0)
+		case r.roles[d.Key] == graphmlLastEdge:
+			r.g.ReserveIDs(0, EdgeID(last))
+		}
+	}
+	for k, v := range r.passthrough(gml.Data, graphmlPrefix, func(d graphmlData) bool { return r.roles[d.Key] != graphmlUnknown }) {
+		r.g.Attrs[k] = v
+	}
+	for i := range gml.Nodes {
+		gn := &gml.Nodes[i]
+		if _, ok := r.nodes[gn.ID]; ok {
+			return fmt.Errorf("GraphML node id %q is used twice", gn.ID)
+		}
+		node := NewNode()
+		node.ID = NodeID(graphmlID(gn.ID, "n"))
+		node.Content = gn.ID
+		if name, ok := r.value(gn.Data, graphmlName); ok {
+			node.Content = name
+		}
+		if label, ok := r.value(gn.Data, graphmlLabel); ok {
+			node.Content = label
+		}
+		x, hasX := r.value(gn.Data, graphmlX)
+		y, hasY := r.value(gn.Data, graphmlY)
+		if hasX && hasY {
+			fx, errX := strconv.ParseFloat(strings.TrimSpace(x), 32)
+			fy, errY := strconv.ParseFloat(strings.TrimSpace(y), 32)
+			if errX != nil || errY != nil {
+				return fmt.Errorf("GraphML node %q has a malformed position", gn.ID)
+			}
+			node.Position = rl.Vector2{X: float32(fx), Y: float32(fy)}
+		}
+		node.Attrs = r.passthrough(gn.Data, graphmlPrefix, func(d graphmlData) bool {
+			return r.roles[d.Key] != graphmlUnknown
+		})
+		r.nodes[gn.ID] = r.g.AddNode(node)
+		if !hasX || !hasY {
+			r.unplaced = append(r.unplaced, r.nodes[gn.ID])
+		}
+		for j := range gn.Graphs {
+			if err := r.readNodes(&gn.Graphs[j]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (r *graphmlReader) readEdges(gml *graphmlGraph) error {
+	for i := range gml.Nodes {
+		for j := range gml.Nodes[i].Graphs {
+			if err := r.readEdges(&gml.Nodes[i].Graphs[j]); err != nil {
+				return err
+			}
+		}
+	}
+	for i := range gml.Edges {
+		ge := &gml.Edges[i]
+		tail, head := r.nodes[ge.Source], r.nodes[ge.Target]
+		if tail == nil || head == nil {
+			return fmt.Errorf("GraphML edge %s -> %s refers to a node that does not exist", ge.Source, ge.Target)
+		}
+		var cost int32
+		numericCost := func(d graphmlData) bool {
+			if r.roles[d.Key] != graphmlCost {
+				return false
+			}
+			f, err := strconv.ParseFloat(strings.TrimSpace(d.Text), 64)
+			return err == nil && f <= math.MaxInt32 && f >= math.MinInt32
+		}
+		for _, d := range ge.Data {
+			if numericCost(d) {
+				f, _ := strconv.ParseFloat(strings.TrimSpace(d.Text), 64)
+				cost = int32(math.Round(f))
+				break
+			}
+		}
+		attrs := r.passthrough(ge.Data, graphmlPrefix, func(d graphmlData) bool {
+			return numericCost(d) || r.roles[d.Key] == graphmlPair
+		})
+		var pairID EdgeID
+		if pair, ok := r.value(ge.Data, graphmlPair); ok {
+			pairID = EdgeID(graphmlID(strings.TrimSpace(pair), "e"))
+		}
+		directed := gml.EdgeDefault != "undirected"
+		if ge.Directed != "" {
+			directed = ge.Directed == "true" || ge.Directed == "1"
+		}
+		edge := r.g.AddEdgeID(EdgeID(graphmlID(ge.ID, "e")), tail, head)
+		edge.Cost = cost
+		edge.Attrs = attrs
+		switch {
+		case !directed && tail == head:
+			r.g.PairEdges(edge, edge)
+		case !directed:
+			reverse := r.g.AddEdgeID(pairID, head, tail)
+			reverse.Cost = cost
+			reverse.Attrs = copyAttributes(attrs)
+			r.g.PairEdges(edge, reverse)
+		case pairID != 0:
+			r.pending = append(r.pending, pendingPair{edge, pairID})
+		}
+	}
+	return nil
+}
+
+// ids written by WriteGraphML carry the node or edge ID, others get a fresh one
+func graphmlID(id, prefix string) uint64 {
+	if !strings.HasPrefix(id, prefix) {
+		return 0
+	}
+	n, err := strconv.ParseUint(strings.TrimPrefix(id, prefix), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// Read a graph from a GraphML document
+func ReadGraphML(rd io.Reader) (Graph, error) {
+	var doc graphmlDocument
+	if err := xml.NewDecoder(rd).Decode(&doc); err != nil {
+		return Graph{}, fmt.Errorf("Malformed GraphML document: %w", err)
+	}
+	g := New()
+	g.Attrs = make(Attributes)
+	r := graphmlReader{
+		g:     &g,
+		keys:  make(map[string]*graphmlKey),
+		roles: make(map[string]graphmlRole),
+		nodes: make(map[string]*Node),
+		used:  make(map[string]bool),
+	}
+	for i := range doc.Keys {
+		key := &doc.Keys[i]
+		id := key.attr("id")
+		if _, ok := r.keys[id]; !ok {
+			r.keyOrder = append(r.keyOrder, id)
+		}
+		r.keys[id] = key
+		r.roles[id] = graphmlKeyRole(key)
+	}
+	for _, a := range doc.Attrs {
+		if a.Name.Space == "xmlns" {
+			g.Attrs[graphmlXmlnsPrefix+a.Name.Local] = a.Value
+		}
+	}
+	for k, v := range r.passthrough(doc.Data, graphmlDocPrefix, func(graphmlData) bool { return false }) {
+		g.Attrs[k] = v
+	}
+	for i := range doc.Graphs {
+		if err := r.readNodes(&doc.Graphs[i]); err != nil {
+			return Graph{}, err
+		}
+	}
+	for i := range doc.Graphs {
+		if err := r.readEdges(&doc.Graphs[i]); err != nil {
+			return Graph{}, err
+		}
+	}
+	resolvePairs(&g, r.pending)
+	arrangeInCircle(r.unplaced)
+	for id := range r.used {
+		if key, ok := r.keys[id]; ok {
+			g.Attrs[graphmlKeyPrefix+id] = key.raw()
+		}
+	}
+	if len(g.Attrs) == 0 {
+		g.Attrs = nil
+	}
+	return g, nil
+}
+
+func graphmlEscape(s string) string {
+	var sb strings.Builder
+	xml.EscapeText(&sb, []byte(s))
+	return sb.String()
+}
+
+func copyAttributes(attrs Attributes) Attributes {
+	if attrs == nil {
+		return nil
+	}
+	ret := make(Attributes, len(attrs))
+	for k, v := range attrs {
+		ret[k] = v
+	}
+	return ret
+}
+
+// the passthrough entries of attrs with the given prefix, sorted by key id
+func graphmlEntries(attrs Attributes, prefix string) [][2]string {
+	entries := make([][2]string, 0)
+	for k, v := range attrs {
+		if !strings.HasPrefix(k, prefix) {
+			continue
+		}
+		id := strings.TrimPrefix(k, prefix)
+		// the other prefixes also start with "graphml:"
+		if prefix == graphmlPrefix && strings.Contains(id, ":") {
+			continue
+		}
+		entries = append(entries, [2]string{id, v})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i][0] < entries[j][0] })
+	return entries
+}
+
+func writeGraphMLData(w io.Writer, indent string, attrs Attributes, prefix string) {
+	for _, entry := range graphmlEntries(attrs, prefix) {
+		fmt.Fprintf(w, "%s<data key=\"%s\">%s</data>\n", indent, graphmlEscape(entry[0]), entry[1])
+	}
+}
+
+// Write the graph as a GraphML document, keeping the data read by ReadGraphML.
+// When every edge is in an undirected pair the graph is written as an undirected
+// graph with one edge per pair, otherwise every edge is written on its own.
+func (g *Graph) WriteGraphML(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	undirected := g.IsUndirected()
+	fmt.Fprintln(bw, `<?xml version="1.0" encoding="UTF-8"?>`)
+	fmt.Fprintf(bw, `<graphml xmlns="%s"`, graphmlNamespace)
+	for _, entry := range graphmlEntries(g.Attrs, graphmlXmlnsPrefix) {
+		fmt.Fprintf(bw, ` xmlns:%s="%s"`, entry[0], graphmlEscape(entry[1]))
+	}
+	fmt.Fprintln(bw, ">")
+	fmt.Fprintf(bw, "  <key id=\"%s\" for=\"node\" attr.name=\"label\" attr.type=\"string\"/>\n", graphmlLabelKey)
+	fmt.Fprintf(bw, "  <key id=\"%s\" for=\"node\" attr.name=\"x\" attr.type=\"float\"/>\n", graphmlXKey)
+	fmt.Fprintf(bw, "  <key id=\"%s\" for=\"node\" attr.name=\"y\" attr.type=\"float\"/>\n", graphmlYKey)
+	fmt.Fprintf(bw, "  <key id=\"%s\" for=\"edge\" attr.name=\"weight\" attr.type=\"int\"/>\n", graphmlCostKey)
+	fmt.Fprintf(bw, "  <key id=\"%s\" for=\"edge\" attr.name=\"%s\" attr.type=\"string\"/>\n", graphmlPairKey, graphmlPairKey)
+	fmt.Fprintf(bw, "  <key id=\"%s\" for=\"graph\" attr.name=\"%s\" attr.type=\"long\"/>\n", graphmlLastNodeKey, graphmlLastNodeKey)
+	fmt.Fprintf(bw, "  <key id=\"%s\" for=\"graph\" attr.name=\"%s\" attr.type=\"long\"/>\n", graphmlLastEdgeKey, graphmlLastEdgeKey)
+	for _, entry := range graphmlEntries(g.Attrs, graphmlKeyPrefix) {
+		fmt.Fprintf(bw, "  %s\n", entry[1])
+	}
+	edgeDefault := "directed"
+	if undirected {
+		edgeDefault = "undirected"
+	}
+	fmt.Fprintf(bw, "  <graph id=\"G\" edgedefault=\"%s\">\n", edgeDefault)
+	lastNode, lastEdge := g.LastIDs()
+	fmt.Fprintf(bw, "    <data key=\"%s\">%d</data>\n", graphmlLastNodeKey, lastNode)
+	fmt.Fprintf(bw, "    <data key=\"%s\">%d</data>\n", graphmlLastEdgeKey, lastEdge)
+	writeGraphMLData(bw, "    ", g.Attrs, graphmlPrefix)
+	ids := make(map[*Node]string, g.NodeCount())
+	for node := range g.Nodes() {
+		ids[node] = fmt.Sprintf("n%d", node.ID)
+		fmt.Fprintf(bw, "    <node id=\"%s\">\n", ids[node])
+		fmt.Fprintf(bw, "      <data key=\"%s\">%s</data>\n", graphmlLabelKey, graphmlEscape(node.Content))
+		fmt.Fprintf(bw, "      <data key=\"%s\">%g</data>\n", graphmlXKey, node.Position.X)
+		fmt.Fprintf(bw, "      <data key=\"%s\">%g</data>\n", graphmlYKey, node.Position.Y)
+		writeGraphMLData(bw, "      ", node.Attrs, graphmlPrefix)
+		fmt.Fprintln(bw, "    </node>")
+	}
+	written := make(map[*Edge]bool, g.EdgeCount())
+	for edge := range g.Edges() {
+		if written[edge] {
+			continue
+		}
+		pair := edge.Pair()
+		if undirected {
+			written[pair] = true
+		}
+		fmt.Fprintf(bw, "    <edge id=\"e%d\" source=\"%s\" target=\"%s\">\n", edge.ID, ids[edge.Tail], ids[edge.Head])
+		fmt.Fprintf(bw, "      <data key=\"%s\">%d</data>\n", graphmlCostKey, edge.Cost)
+		// an undirected loop pairs itself again when it is read
+		if pair != nil && !(undirected && pair == edge) {
+			fmt.Fprintf(bw, "      <data key=\"%s\">e%d</data>\n", graphmlPairKey, pair.ID)
+		}
+		writeGraphMLData(bw, "      ", edge.Attrs, graphmlPrefix)
+		fmt.Fprintln(bw, "    </edge>")
+	}
+	fmt.Fprintln(bw, "  </graph>")
+	writeGraphMLData(bw, "  ", g.Attrs, graphmlDocPrefix)
+	fmt.Fprintln(bw, "</graphml>")
+	return bw.Flush()
+}
@@ -0,0 +1,194 @@
+package graph
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGraphMLFirstKeyDefaultWins(t *testing.T) {
+	src := `<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+		<key id="d1" for="node" attr.name="label"><default>first</default></key>
+		<key id="d0" for="node" attr.name="Label"><default>second</default></key>
+		<key id="d2" for="node" attr.name="label"><default>third</default></key>
+		<graph edgedefault="directed"><node id="a"/></graph>
+	</graphml>`
+	// map order would differ between reads
+	for range 20 {
+		g, err := ReadGraphML(strings.NewReader(src))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if nodes := describeNodes(&g); nodes != "first" {
+			t.Fatalf("the node is labelled %q", nodes)
+		}
+	}
+}
+
+// the Attrs of every node and edge, nodes are named by their content
+func describeAttrs(g *Graph) map[string]Attributes {
+	attrs := map[string]Attributes{"graph": g.Attrs}
+	for n := range g.Nodes() {
+		attrs[n.Content] = n.Attrs
+	}
+	for e := range g.Edges() {
+		attrs[e.Tail.Content+"->"+e.Head.Content] = e.Attrs
+	}
+	return attrs
+}
+
+func TestGraphMLKeepsUnknownData(t *testing.T) {
+	f, err := os.Open("testdata/yed_gephi.graphml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	g, err := ReadGraphML(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nodes := describeNodes(&g); nodes != "Group & friends, Inner, Start, inner1" {
+		t.Errorf("nodes %q", nodes)
+	}
+	if edges := describeEdges(&g); edges != "Group & friends->Start=0, Inner->inner1=3, Start->Inner=7, Start->inner1=4, inner1->Start=4" {
+		t.Errorf("edges %q", edges)
+	}
+	loaded := describeAttrs(&g)
+	for _, test := range []struct {
+		owner, key, contains string
+	}{
+		{"graph", "graphml:d0", "A yEd graph"},
+		{"graph", "graphml:doc:d4", "<y:Resources/>"},
+		{"graph", "graphml:xmlns:y", "http://www.yworks.com/xml/graphml"},
+		{"graph", "graphml:xmlns:yed", "http://www.yworks.com/xml/yed/3"},
+		{"graph", "graphml:xmlns:java", "http://www.yworks.com/xml/yfiles-common/1.0/java"},
+		{"graph", "graphml:key:d3", `yfiles.type="nodegraphics"`},
+		{"graph", "graphml:key:d5", `yfiles.type="edgegraphics"`},
+		{"graph", "graphml:key:size", "<default>10.0</default>"},
+		{"graph", "graphml:key:r", `attr.name="r"`},
+		{"graph", "graphml:key:weight", `attr.type="double"`},
+		{"Start", "graphml:d2", "http://example.com/start"},
+		{"Start", "graphml:d3", `<y:Fill color="#FFCC00" transparent="false"/>`},
+		{"Start", "graphml:size", "24.5"},
+		{"Start", "graphml:r", "153"},
+		{"Start->Inner", "graphml:d5", `<y:Arrows source="none" target="standard"/>`},
+		// opposite edges of the same cost in a directed graph stay two edges
+		{"Start->inner1", "graphml:d5", `color="#FF0000"`},
+		{"inner1->Start", "graphml:d5", `color="#0000FF"`},
+		// not a number, so not a cost either
+		{"Group & friends->Start", "graphml:weight", "heavy"},
+	} {
+		if value, ok := loaded[test.owner][test.key]; !ok || !strings.Contains(value, test.contains) {
+			t.Errorf("%s %s is %q, want it to contain %q", test.owner, test.key, value, test.contains)
+		}
+	}
+	// keys no kept data refers to are not written back
+	for _, key := range []string{"graphml:key:d1", "graphml:key:unused", "graphml:key:label"} {
+		if _, ok := g.Attrs[key]; ok {
+			t.Errorf("%s was kept", key)
+		}
+	}
+
+	var first bytes.Buffer
+	if err := g.WriteGraphML(&first); err != nil {
+		t.Fatal(err)
+	}
+	again, err := ReadGraphML(bytes.NewReader(first.Bytes()))
+	if err != nil {
+		t.Fatalf("%v in\n%s", err, first.String())
+	}
+	if !reflect.DeepEqual(describeAttrs(&again), loaded) {
+		t.Errorf("saving and loading changed the kept data from\n%v\nto\n%v", loaded, describeAttrs(&again))
+	}
+	if describeNodes(&again) != describeNodes(&g) || describeEdges(&again) != describeEdges(&g) {
+		t.Errorf("saving and loading changed the graph to %q %q", describeNodes(&again), describeEdges(&again))
+	}
+	var second bytes.Buffer
+	if err := again.WriteGraphML(&second); err != nil {
+		t.Fatal(err)
+	}
+	if first.String() != second.String() {
+		t.Errorf("the document changed when saved again, from\n%s\nto\n%s", first.String(), second.String())
+	}
+}
+
+func TestGraphMLKeepsDirectedPairsApart(t *testing.T) {
+	src := `<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+		<key id="d0" for="edge" attr.name="color" attr.type="string"/>
+		<key id="w" for="edge" attr.name="weight" attr.type="int"/>
+		<graph edgedefault="directed">
+			<node id="a"/><node id="b"/>
+			<edge id="red" source="a" target="b"><data key="d0">red</data><data key="w">1</data></edge>
+			<edge id="blue" source="b" target="a"><data key="d0">blue</data><data key="w">1</data></edge>
+		</graph>
+	</graphml>`
+	g, err := ReadGraphML(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	colours := func(g *Graph) map[EdgeID]string {
+		colours := make(map[EdgeID]string)
+		for e := range g.Edges() {
+			if e.Pair() != nil || e.Cost != 1 {
+				t.Errorf("edge %d is paired or costs %d", e.ID, e.Cost)
+			}
+			colours[e.ID] = e.Tail.Content + "->" + e.Head.Content + " " + e.Attrs["graphml:d0"]
+		}
+		return colours
+	}
+	want := colours(&g)
+	if len(want) != 2 {
+		t.Fatalf("read %v", want)
+	}
+	var buf bytes.Buffer
+	if err := g.WriteGraphML(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `edgedefault="directed"`) {
+		t.Errorf("written as\n%s", buf.String())
+	}
+	loaded, err := ReadGraphML(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := colours(&loaded); !reflect.DeepEqual(got, want) {
+		t.Errorf("loaded %v, want %v from\n%s", got, want, buf.String())
+	}
+}
+
+func TestGraphMLKeepsPairs(t *testing.T) {
+	for _, mixed := range []bool{false, true} {
+		g := New()
+		a := g.AddNode(NewNode())
+		b := g.AddNode(NewNode())
+		c := g.AddNode(NewNode())
+		ab, ba := g.AddEdge(a, b), g.AddEdge(b, a)
+		bc, cb := g.AddEdge(b, c), g.AddEdge(c, b)
+		g.PairEdges(ab, ba)
+		if !mixed {
+			g.PairEdges(bc, cb)
+		}
+		var buf bytes.Buffer
+		if err := g.WriteGraphML(&buf); err != nil {
+			t.Fatal(err)
+		}
+		if undirected := strings.Contains(buf.String(), `edgedefault="undirected"`); undirected == mixed {
+			t.Errorf("mixed %v written as\n%s", mixed, buf.String())
+		}
+		loaded, err := ReadGraphML(bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range []*Edge{ab, ba, bc, cb} {
+			le := loaded.EdgeByID(e.ID)
+			if le == nil {
+				t.Fatalf("mixed %v: edge %d is missing from\n%s", mixed, e.ID, buf.String())
+			}
+			if (le.Pair() == nil) != (e.Pair() == nil) || (e.Pair() != nil && le.Pair().ID != e.Pair().ID) {
+				t.Errorf("mixed %v: edge %d is paired with %v from\n%s", mixed, e.ID, le.Pair(), buf.String())
+			}
+		}
+	}
+}
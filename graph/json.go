@@ -0,0 +1,162 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// version of the native document format written by WriteJSON
+// 1: edges refer to nodes by their index in the node array
+// 2: nodes and edges carry their ID, edges refer to nodes by ID
+// 3: edges carry the ID of their undirected pair, the highest IDs handed out are saved
+const JSONVersion = 3
+
+type jsonNode struct {
+	ID      NodeID     `json:"id"`
+	Content string     `json:"content"`
+	X       float32    `json:"x"`
+	Y       float32    `json:"y"`
+	Attrs   Attributes `json:"attrs,omitempty"`
+}
+
+type jsonEdge struct {
+	ID EdgeID `json:"id"`
+	// node IDs (indices in version 1 documents), the edge points from tail to head
+	Tail  uint64     `json:"tail"`
+	Head  uint64     `json:"head"`
+	Cost  int32      `json:"cost"`
+	Attrs Attributes `json:"attrs,omitempty"`
+	// the other edge of the undirected pair, 0 for a directed edge
+	Pair EdgeID `json:"pair,omitempty"`
+}
+
+type jsonDocument struct {
+	Version int        `json:"version"`
+	Nodes   []jsonNode `json:"nodes"`
+	Edges   []jsonEdge `json:"edges"`
+	// data kept from other formats, see Attributes
+	Attrs Attributes `json:"attrs,omitempty"`
+	// see Graph.LastIDs
+	LastNodeID NodeID `json:"last_node_id,omitempty"`
+	LastEdgeID EdgeID `json:"last_edge_id,omitempty"`
+}
+
+// Write the graph as a versioned JSON document
+func (g *Graph) WriteJSON(w io.Writer) error {
+	doc := jsonDocument{
+		Version: JSONVersion,
+		Attrs:   g.Attrs,
+		Nodes:   make([]jsonNode, 0, g.NodeCount()),
+		Edges:   make([]jsonEdge, 0, g.EdgeCount()),
+	}
+	doc.LastNodeID, doc.LastEdgeID = g.LastIDs()
+	for node := range g.Nodes() {
+		doc.Nodes = append(doc.Nodes, jsonNode{
+			ID:      node.ID,
+			Content: node.Content,
+			X:       node.Position.X,
+			Y:       node.Position.Y,
+			Attrs:   node.Attrs,
+		})
+	}
+	for edge := range g.Edges() {
+		je := jsonEdge{
+			ID:    edge.ID,
+			Tail:  uint64(edge.Tail.ID),
+			Head:  uint64(edge.Head.ID),
+			Cost:  edge.Cost,
+			Attrs: edge.Attrs,
+		}
+		if pair := edge.Pair(); pair != nil {
+			je.Pair = pair.ID
+		}
+		doc.Edges = append(doc.Edges, je)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(&doc)
+}
+
+// Read a graph from a JSON document created by WriteJSON
+func ReadJSON(r io.Reader) (Graph, error) {
+	var doc jsonDocument
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return Graph{}, fmt.Errorf("Malformed graph document: %w", err)
+	}
+	if doc.Version < 1 || doc.Version > JSONVersion {
+		return Graph{}, fmt.Errorf("Unsupported graph document version %d", doc.Version)
+	}
+	g := New()
+	g.Attrs = doc.Attrs
+	g.ReserveIDs(doc.LastNodeID, doc.LastEdgeID)
+	// how edges of the document refer to nodes
+	nodes := make(map[uint64]*Node, len(doc.Nodes))
+	for i, jn := range doc.Nodes {
+		node := NewNode()
+		if doc.Version >= 2 {
+			if jn.ID == 0 {
+				return Graph{}, fmt.Errorf("Node %d has no id", i)
+			}
+			if _, ok := nodes[uint64(jn.ID)]; ok {
+				return Graph{}, fmt.Errorf("Node id %d is used twice", jn.ID)
+			}
+			node.ID = jn.ID
+		}
+		node.Content = jn.Content
+		node.Position = rl.Vector2{X: jn.X, Y: jn.Y}
+		node.Attrs = jn.Attrs
+		added := g.AddNode(node)
+		if doc.Version >= 2 {
+			nodes[uint64(jn.ID)] = added
+		} else {
+			nodes[uint64(i)] = added
+		}
+	}
+	edges := make([]*Edge, len(doc.Edges))
+	for i, je := range doc.Edges {
+		tail, head := nodes[je.Tail], nodes[je.Head]
+		if tail == nil || head == nil {
+			return Graph{}, fmt.Errorf("Edge %d refers to a node that does not exist", i)
+		}
+		if doc.Version >= 2 && g.EdgeByID(je.ID) != nil {
+			return Graph{}, fmt.Errorf("Edge id %d is used twice", je.ID)
+		}
+		edge := g.AddEdgeID(je.ID, tail, head)
+		edge.Cost = je.Cost
+		edge.Attrs = je.Attrs
+		edges[i] = edge
+	}
+	if doc.Version < 3 {
+		pairLegacyEdges(&g)
+		return g, nil
+	}
+	for i, je := range doc.Edges {
+		if je.Pair == 0 {
+			continue
+		}
+		edge, pair := edges[i], g.EdgeByID(je.Pair)
+		if pair == nil || (pair.Pair() != nil && pair.Pair() != edge) || !g.PairEdges(edge, pair) {
+			return Graph{}, fmt.Errorf("Edge %d can not be paired with edge %d", i, je.Pair)
+		}
+	}
+	return g, nil
+}
+
+// Documents before version 3 did not save undirected pairs, the edges created in
+// UNDIRECTED connect mode were told apart by going the opposite way at the same cost
+func pairLegacyEdges(g *Graph) {
+	for edge := range g.Edges() {
+		if edge.Pair() != nil {
+			continue
+		}
+		for other := range edge.Head.Out() {
+			if other.Head == edge.Tail && other.Cost == edge.Cost && other.Pair() == nil {
+				g.PairEdges(edge, other)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package graph
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	g := New()
+	g.Attrs = Attributes{"graphml:key": "value"}
+	a := g.AddNode(NewNode())
+	a.Content = "a"
+	a.Position.X, a.Position.Y = 10, -20
+	a.Attrs = Attributes{"dot:shape": "box"}
+	b := g.AddNode(NewNode())
+	b.Content = "b"
+	ab := g.AddEdge(a, b)
+	ab.Cost = 7
+	ab.Attrs = Attributes{"dot:color": "red"}
+	g.AddEdge(b, a).Cost = -2
+
+	var buf bytes.Buffer
+	if err := g.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := ReadJSON(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Attrs["graphml:key"] != "value" {
+		t.Errorf("graph attributes %v", loaded.Attrs)
+	}
+	la, lb := loaded.NodeByID(a.ID), loaded.NodeByID(b.ID)
+	if la == nil || lb == nil {
+		t.Fatal("nodes are missing")
+	}
+	if la.Content != "a" || la.Position.X != 10 || la.Position.Y != -20 || la.Attrs["dot:shape"] != "box" {
+		t.Errorf("node a loaded as %+v", *la)
+	}
+	lab := loaded.EdgeBetween(la, lb)
+	if lab == nil || lab.Cost != 7 || lab.Attrs["dot:color"] != "red" {
+		t.Fatalf("edge a b loaded as %+v", lab)
+	}
+	if lba := loaded.EdgeBetween(lb, la); lba == nil || lba.Cost != -2 {
+		t.Errorf("edge b a loaded as %+v", lba)
+	}
+}
+
+func TestJSONVersion1(t *testing.T) {
+	// version 1 documents have no ids, edges refer to the node array
+	doc := `{"version": 1,
+		"nodes": [{"content": "a"}, {"content": "b"}, {"content": "c"}],
+		"edges": [{"tail": 2, "head": 0, "cost": 4}, {"tail": 0, "head": 1}]}`
+	g, err := ReadJSON(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodes := make(map[string]*Node)
+	for n := range g.Nodes() {
+		nodes[n.Content] = n
+	}
+	if len(nodes) != 3 {
+		t.Fatalf("loaded %d nodes", len(nodes))
+	}
+	if e := g.EdgeBetween(nodes["c"], nodes["a"]); e == nil || e.Cost != 4 {
+		t.Errorf("edge c a loaded as %+v", e)
+	}
+	if g.EdgeBetween(nodes["a"], nodes["b"]) == nil {
+		t.Error("edge a b is missing")
+	}
+	if g.EdgeCount() != 2 {
+		t.Errorf("loaded %d edges", g.EdgeCount())
+	}
+}
+
+func TestJSONErrors(t *testing.T) {
+	for _, test := range []struct {
+		doc, err string
+	}{
+		{`{"version": 4, "nodes": [], "edges": []}`, "Unsupported graph document version 4"},
+		{`{"version": 3, "nodes": [{"id": 1}, {"id": 2}],
+			"edges": [{"id": 1, "tail": 1, "head": 2, "pair": 1}]}`, "Edge 0 can not be paired with edge 1"},
+		{`{"version": 3, "nodes": [{"id": 1}, {"id": 2}],
+			"edges": [{"id": 1, "tail": 1, "head": 2, "pair": 7}]}`, "Edge 0 can not be paired with edge 7"},
+		{`{"version": 3, "nodes": [{"id": 1}, {"id": 2}],
+			"edges": [{"id": 1, "tail": 1, "head": 2, "pair": 2}, {"id": 2, "tail": 2, "head": 1, "pair": 1},
+			{"id": 3, "tail": 2, "head": 1, "pair": 1}]}`, "Edge 2 can not be paired with edge 1"},
+		{`{"version": 2, "nodes": [{"id": 1}, {"id": 1}], "edges": []}`, "Node id 1 is used twice"},
+		{`{"version": 2, "nodes": [{"content": "a"}], "edges": []}`, "Node 0 has no id"},
+		{`{"version": 2, "nodes": [{"id": 1}, {"id": 2}],
+			"edges": [{"id": 1, "tail": 1, "head": 2}, {"id": 1, "tail": 2, "head": 1}]}`, "Edge id 1 is used twice"},
+		{`{"version": 2, "nodes": [{"id": 1}], "edges": [{"id": 1, "tail": 1, "head": 5}]}`, "Edge 0 refers to a node that does not exist"},
+		{`{"version": 1, "nodes": [{}], "edges": [{"tail": 1, "head": 0}]}`, "Edge 0 refers to a node that does not exist"},
+	} {
+		if _, err := ReadJSON(strings.NewReader(test.doc)); err == nil || err.Error() != test.err {
+			t.Errorf("%s: got %v, want %q", test.doc, err, test.err)
+		}
+	}
+	if _, err := ReadJSON(strings.NewReader("{")); err == nil || !strings.HasPrefix(err.Error(), "Malformed graph document") {
+		t.Errorf("truncated document: got %v", err)
+	}
+}
+
+func TestJSONKeepsPairs(t *testing.T) {
+	g := New()
+	a := g.AddNode(NewNode())
+	b := g.AddNode(NewNode())
+	ab, ba := g.AddEdge(a, b), g.AddEdge(b, a)
+	g.PairEdges(ab, ba)
+	// same cost the other way but directed
+	c := g.AddNode(NewNode())
+	g.AddEdge(b, c)
+	g.AddEdge(c, b)
+	var buf bytes.Buffer
+	if err := g.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := ReadJSON(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pair := loaded.EdgeByID(ab.ID).Pair(); pair == nil || pair.ID != ba.ID {
+		t.Errorf("edge %d is paired with %v", ab.ID, pair)
+	}
+	for e := range loaded.Edges() {
+		if e.Tail == loaded.NodeByID(c.ID) || e.Head == loaded.NodeByID(c.ID) {
+			if e.Pair() != nil {
+				t.Errorf("directed edge %d was paired", e.ID)
+			}
+		}
+	}
+}
+
+func TestJSONPairsLegacyEdges(t *testing.T) {
+	// before version 3 edges the opposite way at the same cost were a pair
+	doc := `{"version": 2, "nodes": [{"id": 1}, {"id": 2}, {"id": 3}],
+		"edges": [{"id": 1, "tail": 1, "head": 2, "cost": 4}, {"id": 2, "tail": 2, "head": 1, "cost": 4},
+		{"id": 3, "tail": 2, "head": 3, "cost": 1}, {"id": 4, "tail": 3, "head": 2, "cost": 2}]}`
+	g, err := ReadJSON(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pair := g.EdgeByID(1).Pair(); pair == nil || pair.ID != 2 {
+		t.Errorf("edge 1 is paired with %v", pair)
+	}
+	if g.EdgeByID(3).Pair() != nil || g.EdgeByID(4).Pair() != nil {
+		t.Error("edges of different costs were paired")
+	}
+}
@@ -0,0 +1,196 @@
+package history
+
+import (
+	"graphographic/algorithm"
+	gr "graphographic/graph"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+type AddNode struct {
+	// the node to add, its ID is filled in on Apply
+	N gr.Node
+}
+
+func (c *AddNode) Apply(g *gr.Graph) {
+	c.N.ID = g.AddNode(c.N).ID
+}
+func (c *AddNode) Revert(g *gr.Graph) {
+	if node := g.NodeByID(c.N.ID); node != nil {
+		c.N = *node
+		g.RemoveNode(node)
+	}
+}
+
+type RemoveNode struct {
+	ID gr.NodeID
+	// copy of the node taken before it was removed
+	n gr.Node
+	// the edges that were removed with the node
+	edges []*RemoveEdge
+}
+
+func (c *RemoveNode) Apply(g *gr.Graph) {
+	node := g.NodeByID(c.ID)
+	if node == nil {
+		return
+	}
+	c.n = *node
+	c.edges = c.edges[:0]
+	incident := make([]gr.EdgeID, 0, node.OutDegree()+node.InDegree())
+	for edge := range node.Out() {
+		incident = append(incident, edge.ID)
+	}
+	for edge := range node.In() {
+		// a loop is both outgoing and incoming
+		if edge.Tail != node {
+			incident = append(incident, edge.ID)
+		}
+	}
+	for _, id := range incident {
+		removal := &RemoveEdge{ID: id}
+		removal.Apply(g)
+		c.edges = append(c.edges, removal)
+	}
+	g.RemoveNode(node)
+}
+func (c *RemoveNode) Revert(g *gr.Graph) {
+	c.ID = g.AddNode(c.n).ID
+	for i := len(c.edges) - 1; i >= 0; i-- {
+		c.edges[i].Revert(g)
+	}
+}
+
+type AddEdge struct {
+	// filled in on Apply
+	ID gr.EdgeID
+	Tail, Head gr.NodeID
+	Cost int32
+	// the edge going the other way this one makes an undirected pair with, 0 for none
+	Pair gr.EdgeID
+}
+
+func (c *AddEdge) Apply(g *gr.Graph) {
+	tail, head := g.NodeByID(c.Tail), g.NodeByID(c.Head)
+	if tail != nil && head != nil {
+		edge := g.AddEdgeID(c.ID, tail, head)
+		edge.Cost = c.Cost
+		c.ID = edge.ID
+		if pair := g.EdgeByID(c.Pair); pair != nil {
+			g.PairEdges(edge, pair)
+		}
+	}
+}
+func (c *AddEdge) Revert(g *gr.Graph) {
+	if edge := g.EdgeByID(c.ID); edge != nil {
+		c.Cost = edge.Cost
+		g.RemoveEdge(edge)
+	}
+}
+
+type RemoveEdge struct {
+	ID gr.EdgeID
+	// what is needed to put the edge back
+	tail, head gr.NodeID
+	cost int32
+	attrs gr.Attributes
+	// the undirected pair, restored when that edge is still or again in the graph
+	pair gr.EdgeID
+}
+
+func (c *RemoveEdge) Apply(g *gr.Graph) {
+	if edge := g.EdgeByID(c.ID); edge != nil {
+		c.tail, c.head = edge.Tail.ID, edge.Head.ID
+		c.cost, c.attrs = edge.Cost, edge.Attrs
+		c.pair = 0
+		if pair := edge.Pair(); pair != nil {
+			c.pair = pair.ID
+		}
+		g.RemoveEdge(edge)
+	}
+}
+func (c *RemoveEdge) Revert(g *gr.Graph) {
+	tail, head := g.NodeByID(c.tail), g.NodeByID(c.head)
+	if tail != nil && head != nil {
+		edge := g.AddEdgeID(c.ID, tail, head)
+		edge.Cost, edge.Attrs = c.cost, c.attrs
+		if c.pair == c.ID {
+			g.PairEdges(edge, edge)
+		} else if pair := g.EdgeByID(c.pair); pair != nil && pair.Pair() == nil {
+			g.PairEdges(edge, pair)
+		}
+		c.ID = edge.ID
+	}
+}
+
+// The edit commands hold the value the graph does not have, applying or
+// reverting them swaps it with the one in the graph
+
+type EditEdgeCost struct {
+	ID gr.EdgeID
+	Cost int32
+}
+
+func (c *EditEdgeCost) Apply(g *gr.Graph) {
+	if edge := g.EdgeByID(c.ID); edge != nil {
+		edge.Cost, c.Cost = c.Cost, edge.Cost
+	}
+}
+func (c *EditEdgeCost) Revert(g *gr.Graph) {
+	c.Apply(g)
+}
+
+type EditNodeContent struct {
+	ID gr.NodeID
+	Content string
+}
+
+func (c *EditNodeContent) Apply(g *gr.Graph) {
+	if node := g.NodeByID(c.ID); node != nil {
+		node.Content, c.Content = c.Content, node.Content
+	}
+}
+func (c *EditNodeContent) Revert(g *gr.Graph) {
+	c.Apply(g)
+}
+
+type MoveNode struct {
+	ID gr.NodeID
+	Pos rl.Vector2
+}
+
+func (c *MoveNode) Apply(g *gr.Graph) {
+	if node := g.NodeByID(c.ID); node != nil {
+		node.Position, c.Pos = c.Pos, node.Position
+	}
+}
+func (c *MoveNode) Revert(g *gr.Graph) {
+	c.Apply(g)
+}
+
+// Selecting a node for an algorithm, like the start node of Dijkstra
+type NodeSelected struct {
+	ID gr.NodeID
+	Algo algorithm.Algorithm
+}
+
+func (c *NodeSelected) Apply(g *gr.Graph) {
+	if node := g.NodeByID(c.ID); node != nil {
+		c.Algo.NodeSelected(node)
+	}
+}
+func (c *NodeSelected) Revert(g *gr.Graph) {
+	c.Algo.UndoSelect()
+}
+
+// Replacing the whole graph, like with the condensation of its components
+type ReplaceGraph struct {
+	G gr.Graph
+}
+
+func (c *ReplaceGraph) Apply(g *gr.Graph) {
+	*g, c.G = c.G, *g
+}
+func (c *ReplaceGraph) Revert(g *gr.Graph) {
+	c.Apply(g)
+}
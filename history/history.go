@@ -2,33 +2,129 @@ package history
 
 import (
 	gr "graphographic/graph"
-	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
-type AddNode struct {
-	N *gr.Node
+// An action done to the graph that can be undone and done again. Commands refer to
+// nodes and edges by ID so they stay valid when the graph hands out new pointers,
+// e.g. after a removed node is restored
+type Command interface {
+	Apply(g *gr.Graph)
+	Revert(g *gr.Graph)
 }
-type RemoveNode struct {
-	N *gr.Node
+
+// Undo and redo stacks of commands
+type History struct {
+	undo []Command
+	redo []Command
+	// how many commands can be undone, 0 means no limit
+	Depth int
+	// commands of the open transaction and how deeply it is nested
+	pending Group
+	open int
+}
+
+// Commands undone and redone as one step
+type Group []Command
+
+func (c Group) Apply(g *gr.Graph) {
+	for _, cmd := range c {
+		cmd.Apply(g)
+	}
+}
+func (c Group) Revert(g *gr.Graph) {
+	for i := len(c) - 1; i >= 0; i-- {
+		c[i].Revert(g)
+	}
 }
-type AddEdge struct {
-	E *gr.Edge
+
+func New(depth int) *History {
+	return &History{
+		undo:  make([]Command, 0),
+		redo:  make([]Command, 0),
+		Depth: depth,
+	}
 }
-type RemoveEdge struct {
-	E *gr.Edge
+
+// Apply the command and put it on the undo stack
+func (h *History) Do(g *gr.Graph, c Command) {
+	c.Apply(g)
+	h.Record(c)
 }
-type EditEdgeCost struct {
-	E *gr.Edge
-	CostPreChange int32
+
+// Put a command that was already applied on the undo stack, used when the change
+// is made gradually by the user, like dragging a node or typing its content.
+// Anything that could be redone is dropped
+func (h *History) Record(c Command) {
+	if h.open > 0 {
+		h.pending = append(h.pending, c)
+		return
+	}
+	h.undo = append(h.undo, c)
+	if h.Depth > 0 && len(h.undo) > h.Depth {
+		h.undo = h.undo[len(h.undo)-h.Depth:]
+	}
+	h.redo = h.redo[:0]
 }
-type EditNodeContent struct {
-	N *gr.Node
-	ContentPreChange string
+
+// Start a transaction, everything done until the matching End becomes a single
+// step of the history. Transactions can be nested, the outermost one makes the step
+func (h *History) Begin() {
+	h.open++
 }
-type MoveNode struct {
-	N *gr.Node
-	PosPreChange rl.Vector2
+
+// Close the transaction opened by Begin
+func (h *History) End() {
+	if h.open == 0 {
+		return
+	}
+	h.open--
+	if h.open > 0 || len(h.pending) == 0 {
+		return
+	}
+	group := h.pending
+	h.pending = nil
+	if len(group) == 1 {
+		h.Record(group[0])
+	} else {
+		h.Record(group)
+	}
 }
-type NodeSelected struct {
-	N *gr.Node
+
+// Revert the latest command, false when there was nothing to undo or a transaction is open
+func (h *History) Undo(g *gr.Graph) bool {
+	if len(h.undo) == 0 || h.open > 0 {
+		return false
+	}
+	c := h.undo[len(h.undo)-1]
+	h.undo = h.undo[:len(h.undo)-1]
+	c.Revert(g)
+	h.redo = append(h.redo, c)
+	return true
+}
+
+// Apply the latest undone command again, false when there was nothing to redo or a transaction is open
+func (h *History) Redo(g *gr.Graph) bool {
+	if len(h.redo) == 0 || h.open > 0 {
+		return false
+	}
+	c := h.redo[len(h.redo)-1]
+	h.redo = h.redo[:len(h.redo)-1]
+	c.Apply(g)
+	h.undo = append(h.undo, c)
+	return true
+}
+
+func (h *History) Clear() {
+	h.undo = h.undo[:0]
+	h.redo = h.redo[:0]
+	h.pending = nil
+	h.open = 0
+}
+
+func (h *History) CanUndo() bool {
+	return len(h.undo) > 0
+}
+
+func (h *History) CanRedo() bool {
+	return len(h.redo) > 0
 }
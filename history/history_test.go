@@ -0,0 +1,191 @@
+package history
+
+import (
+	gr "graphographic/graph"
+	"testing"
+)
+
+func TestUndoRedo(t *testing.T) {
+	g := gr.New()
+	h := New(0)
+	a := &AddNode{N: gr.NewNode()}
+	b := &AddNode{N: gr.NewNode()}
+	h.Do(&g, a)
+	h.Do(&g, b)
+	h.Do(&g, &AddEdge{Tail: a.N.ID, Head: b.N.ID, Cost: 3})
+	h.Do(&g, &EditNodeContent{ID: a.N.ID, Content: "A"})
+
+	for h.Undo(&g) {
+	}
+	if g.NodeCount() != 0 || g.EdgeCount() != 0 {
+		t.Fatalf("after undoing everything the graph has %d nodes and %d edges", g.NodeCount(), g.EdgeCount())
+	}
+	for h.Redo(&g) {
+	}
+	nodeA, nodeB := g.NodeByID(a.N.ID), g.NodeByID(b.N.ID)
+	if nodeA == nil || nodeB == nil || nodeA.Content != "A" {
+		t.Fatal("redo did not bring the nodes back as they were")
+	}
+	if edge := g.EdgeBetween(nodeA, nodeB); edge == nil || edge.Cost != 3 {
+		t.Fatal("redo did not bring the edge back as it was")
+	}
+}
+
+func TestRecordDropsRedo(t *testing.T) {
+	g := gr.New()
+	h := New(0)
+	h.Do(&g, &AddNode{N: gr.NewNode()})
+	h.Undo(&g)
+	h.Do(&g, &AddNode{N: gr.NewNode()})
+	if h.CanRedo() {
+		t.Fatal("a new action should drop the undone ones")
+	}
+}
+
+func TestDepth(t *testing.T) {
+	g := gr.New()
+	h := New(2)
+	for range 5 {
+		h.Do(&g, &AddNode{N: gr.NewNode()})
+	}
+	undone := 0
+	for h.Undo(&g) {
+		undone++
+	}
+	if undone != 2 || g.NodeCount() != 3 {
+		t.Fatalf("undid %d actions leaving %d nodes, want 2 and 3", undone, g.NodeCount())
+	}
+}
+
+func TestTransaction(t *testing.T) {
+	g := gr.New()
+	h := New(0)
+	h.Begin()
+	a := &AddNode{N: gr.NewNode()}
+	h.Do(&g, a)
+	h.Begin()
+	b := &AddNode{N: gr.NewNode()}
+	h.Do(&g, b)
+	h.Do(&g, &AddEdge{Tail: a.N.ID, Head: b.N.ID})
+	h.End()
+	if h.CanUndo() || h.Undo(&g) {
+		t.Fatal("nothing can be undone while a transaction is open")
+	}
+	h.Do(&g, &AddEdge{Tail: b.N.ID, Head: a.N.ID})
+	h.End()
+
+	if !h.Undo(&g) || g.NodeCount() != 0 || g.EdgeCount() != 0 {
+		t.Fatal("a single undo should revert the whole transaction")
+	}
+	if h.CanUndo() {
+		t.Fatal("the transaction should be a single step")
+	}
+	if !h.Redo(&g) || g.NodeCount() != 2 || g.EdgeCount() != 2 {
+		t.Fatal("a single redo should apply the whole transaction")
+	}
+}
+
+// the edges of a deleted node, incoming, outgoing and loops, come back on undo
+func TestRemoveNodeRestoresEdges(t *testing.T) {
+	g := gr.New()
+	a := g.AddNode(gr.NewNode())
+	b := g.AddNode(gr.NewNode())
+	c := g.AddNode(gr.NewNode())
+	g.AddEdge(a, b).Cost = 1
+	g.AddEdge(b, a).Cost = 1
+	g.AddEdge(c, b).Cost = 2
+	loop := g.AddEdge(b, b)
+	loop.Cost = 3
+	loop.Attrs = gr.Attributes{"dot:color": "red"}
+	g.AddEdge(a, c).Cost = 4
+	want := edgeSet(&g)
+
+	h := New(0)
+	h.Do(&g, &RemoveNode{ID: b.ID})
+	if g.NodeCount() != 2 || g.EdgeCount() != 1 {
+		t.Fatalf("after removing b the graph has %d nodes and %d edges, want 2 and 1", g.NodeCount(), g.EdgeCount())
+	}
+	for range 2 {
+		h.Undo(&g)
+		if got := edgeSet(&g); !sameEdges(got, want) {
+			t.Fatalf("undo restored edges %v, want %v", got, want)
+		}
+		if g.NodeByID(b.ID) == nil {
+			t.Fatal("undo did not restore the node with its id")
+		}
+		h.Redo(&g)
+		if g.EdgeCount() != 1 {
+			t.Fatalf("redo left %d edges, want 1", g.EdgeCount())
+		}
+	}
+	h.Undo(&g)
+	restored := g.EdgeByID(loop.ID)
+	if restored == nil || restored.Attrs["dot:color"] != "red" {
+		t.Fatal("the loop lost its attributes")
+	}
+}
+
+type edgeRecord struct {
+	id         gr.EdgeID
+	tail, head gr.NodeID
+	cost       int32
+}
+
+func edgeSet(g *gr.Graph) map[gr.EdgeID]edgeRecord {
+	set := make(map[gr.EdgeID]edgeRecord)
+	for edge := range g.Edges() {
+		set[edge.ID] = edgeRecord{edge.ID, edge.Tail.ID, edge.Head.ID, edge.Cost}
+	}
+	return set
+}
+
+func sameEdges(a, b map[gr.EdgeID]edgeRecord) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for id, rec := range a {
+		if b[id] != rec {
+			return false
+		}
+	}
+	return true
+}
+
+func TestUndoKeepsPairs(t *testing.T) {
+	g := gr.New()
+	h := New(0)
+	a := &AddNode{N: gr.NewNode()}
+	b := &AddNode{N: gr.NewNode()}
+	h.Do(&g, a)
+	h.Do(&g, b)
+	forward := &AddEdge{Tail: a.N.ID, Head: b.N.ID}
+	h.Do(&g, forward)
+	reverse := &AddEdge{Tail: b.N.ID, Head: a.N.ID, Pair: forward.ID}
+	h.Do(&g, reverse)
+	paired := func() bool {
+		e := g.EdgeByID(forward.ID)
+		return e != nil && e.Pair() != nil && e.Pair().ID == reverse.ID
+	}
+	if !paired() {
+		t.Fatal("the edges were not paired")
+	}
+	h.Do(&g, &RemoveNode{ID: a.N.ID})
+	h.Undo(&g)
+	if !paired() {
+		t.Fatal("undoing the removal of a node did not restore the pair")
+	}
+	h.Do(&g, &RemoveEdge{ID: reverse.ID})
+	h.Undo(&g)
+	if !paired() {
+		t.Fatal("undoing the removal of an edge did not restore the pair")
+	}
+	for h.Undo(&g) {
+	}
+	// the nodes and edges, not the removal that was undone
+	for range 4 {
+		h.Redo(&g)
+	}
+	if !paired() {
+		t.Fatal("redo did not restore the pair")
+	}
+}
@@ -1,12 +1,16 @@
 package main
 
 import (
-	"container/list"
+	"flag"
 	"fmt"
 	algo "graphographic/algorithm"
 	gr "graphographic/graph"
 	hist "graphographic/history"
+	tl "graphographic/timeline"
 	"math"
+	"os"
+	"path/filepath"
+	"slices"
 	"strconv"
 	"unicode"
 
@@ -15,13 +19,18 @@ import (
 )
 
 const (
-	FONT_SIZE               = 24
-	FONT_SPACING            = 6
-	SCALE_MINIMUM           = 10. / float32(FONT_SIZE)
-	LINE_THICKNESS          = 4.0
-	TARGET_FPS              = 60
-	ACTION_HISTORY_MAX_SIZE = 40
-	MIN_RADIUS              = 10
+	FONT_SIZE             = 24
+	FONT_SPACING          = 6
+	SCALE_MINIMUM         = 10. / float32(FONT_SIZE)
+	LINE_THICKNESS        = 4.0
+	TARGET_FPS            = 60
+	DEFAULT_HISTORY_DEPTH = 40
+	MIN_RADIUS            = 10
+	DEFAULT_FILE_NAME     = "graph.json"
+	// at most this many nodes are shown in the matrix panel
+	MATRIX_PANEL_MAX_NODES = 16
+	// longer lines of the result panel are cut off, the copied text has all of it
+	RESULT_PANEL_MAX_CHARS = 80
 )
 const (
 	MODE_PLACE     = iota
@@ -34,24 +43,25 @@ const (
 )
 
 var (
-	Width                          = 800
-	Height                         = 600
-	Graph                          = gr.New()
-	Scale               float32    = 1.0
-	Offset              rl.Vector2 = rl.Vector2Zero()
-	Center              rl.Vector2 = rl.Vector2Scale(rl.Vector2{X: float32(Width), Y: float32(Height)}, 0.5)
-	BackgroundColor                = rl.White
-	GraphColor                     = rl.Black
-	SelectedNodeColor              = rl.SkyBlue
-	Mode                int32      = MODE_PLACE
-	NodeA               *gr.Node   = nil
-	NodeB               *gr.Node   = nil
-	EdgeA               *gr.Edge   = nil
-	SelectedEdgeScratch string     = ""
-	Directed            bool       = false
-	GridGrain           float32    = 12
-	GridSpacing         float32    = float32(Width) / GridGrain
-	ActionHistory       []any      = make([]any, 0)
+	Width                             = 800
+	Height                            = 600
+	Graph                             = gr.New()
+	Scale               float32       = 1.0
+	Offset              rl.Vector2    = rl.Vector2Zero()
+	Center              rl.Vector2    = rl.Vector2Scale(rl.Vector2{X: float32(Width), Y: float32(Height)}, 0.5)
+	BackgroundColor                   = rl.White
+	GraphColor                        = rl.Black
+	SelectedNodeColor                 = rl.SkyBlue
+	Mode                int32         = MODE_PLACE
+	NodeA               *gr.Node      = nil
+	NodeB               *gr.Node      = nil
+	EdgeA               *gr.Edge      = nil
+	EdgeAPair           *gr.Edge      = nil
+	SelectedEdgeScratch string        = ""
+	Directed            bool          = false
+	GridGrain           float32       = 12
+	GridSpacing         float32       = float32(Width) / GridGrain
+	History             *hist.History = hist.New(DEFAULT_HISTORY_DEPTH)
 	// mouse position in screen space
 	MousePos             rl.Vector2
 	Algorithms           []algo.Algorithm = make([]algo.Algorithm, 0)
@@ -60,6 +70,16 @@ var (
 	IsAlgorithmRunning   bool             = false
 	AlgorithmSpeed       int              = 30
 	AlgorithmErrorMsg    string           = ""
+	// outcome of the last run that finished, nil while running or after a failure
+	AlgorithmResult *algo.Result = nil
+	// steps of the current run to go back and forth through, nil when nothing ran
+	Timeline          *tl.Timeline = nil
+	IsAlgorithmPaused bool         = false
+	// table of a Matrix algorithm shown in the side panel
+	MatrixTable int = 0
+	// file the graph was loaded from or last saved to
+	CurrentFile string = ""
+	StatusMsg   string = ""
 
 	UpdateCounter uint64 = 0
 )
@@ -85,12 +105,22 @@ func wrap[T constraints.Ordered](value, min, max T) T {
 }
 
 func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [options] [graph file]\n", filepath.Base(os.Args[0]))
+		flag.PrintDefaults()
+	}
+	flag.IntVar(&History.Depth, "history", DEFAULT_HISTORY_DEPTH, "how many actions can be undone, 0 for no limit")
+	flag.Parse()
 	rl.SetConfigFlags(rl.FlagWindowResizable)
 	rl.InitWindow(int32(Width), int32(Height), "Graphographic")
 	rl.SetTargetFPS(TARGET_FPS)
-	initGraph()
 	loadAlgorithms()
-	spreadNodes()
+	if flag.NArg() > 0 {
+		openGraph(flag.Arg(0))
+	} else {
+		initGraph()
+		spreadNodes()
+	}
 	for !rl.WindowShouldClose() {
 		rl.BeginDrawing()
 		rl.ClearBackground(BackgroundColor)
@@ -137,31 +167,114 @@ func loadAlgorithms() {
 	Algorithms = append(Algorithms, dfs)
 	bfs := &algo.BFS{}
 	Algorithms = append(Algorithms, bfs)
+	cycleDetection := &algo.CycleDetection{}
+	Algorithms = append(Algorithms, cycleDetection)
 	dijkstra := &algo.Dijkstra{}
 	Algorithms = append(Algorithms, dijkstra)
+	bellmanFord := &algo.BellmanFord{}
+	Algorithms = append(Algorithms, bellmanFord)
+	aStar := &algo.AStar{Heuristic: algo.HeuristicScaled}
+	Algorithms = append(Algorithms, aStar)
+	floydWarshall := &algo.FloydWarshall{}
+	Algorithms = append(Algorithms, floydWarshall)
+	prim := &algo.Prim{}
+	Algorithms = append(Algorithms, prim)
+	kruskal := &algo.Kruskal{}
+	Algorithms = append(Algorithms, kruskal)
+	topoSort := &algo.TopoSort{}
+	Algorithms = append(Algorithms, topoSort)
+	scc := &algo.SCC{}
+	Algorithms = append(Algorithms, scc)
+	bridges := &algo.Bridges{}
+	Algorithms = append(Algorithms, bridges)
+	edmondsKarp := &algo.EdmondsKarp{}
+	Algorithms = append(Algorithms, edmondsKarp)
+	dinic := &algo.Dinic{}
+	Algorithms = append(Algorithms, dinic)
+	bipartite := &algo.Bipartite{}
+	Algorithms = append(Algorithms, bipartite)
+	hopcroftKarp := &algo.HopcroftKarp{}
+	Algorithms = append(Algorithms, hopcroftKarp)
+	colouring := &algo.Colouring{}
+	Algorithms = append(Algorithms, colouring)
+	euler := &algo.Euler{}
+	Algorithms = append(Algorithms, euler)
+	hamiltonian := &algo.Hamiltonian{}
+	Algorithms = append(Algorithms, hamiltonian)
+	tsp := &algo.TSP{}
+	Algorithms = append(Algorithms, tsp)
+
+	CurrentAlgorithmName = algorithmName()
+}
 
-	CurrentAlgorithmName = Algorithms[CurrentAlgorithm].GetName()
+// name of the current algorithm with the option picked for it
+func algorithmName() string {
+	name := Algorithms[CurrentAlgorithm].GetName()
+	if conf, ok := Algorithms[CurrentAlgorithm].(algo.Configurable); ok {
+		name += " (" + conf.OptionName() + ")"
+	}
+	return name
 }
 
 func resetAlgoDataState() {
-	for nodeIt := Graph.Nodes.Front(); nodeIt != nil; nodeIt = nodeIt.Next() {
-		n := nodeIt.Value.(*gr.Node)
+	AlgorithmResult = nil
+	Timeline = nil
+	IsAlgorithmPaused = false
+	for n := range Graph.Nodes() {
 		n.Data = gr.AlgoData{}
 	}
-	for edgeIt := Graph.Edges.Front(); edgeIt != nil; edgeIt = edgeIt.Next() {
-		e := edgeIt.Value.(*gr.Edge)
+	for e := range Graph.Edges() {
 		e.Data = gr.AlgoData{}
 	}
 }
 
+func openGraph(path string) {
+	g, err := gr.LoadFile(path)
+	if err != nil {
+		rl.TraceLog(rl.LogWarning, "%s", err.Error())
+		StatusMsg = "Could not open " + filepath.Base(path)
+		return
+	}
+	Graph = g
+	CurrentFile = path
+	History.Clear()
+	NodeA = nil
+	NodeB = nil
+	EdgeA = nil
+	EdgeAPair = nil
+	IsAlgorithmRunning = false
+	resetAlgoDataState()
+	Algorithms[CurrentAlgorithm].Init()
+	rl.SetWindowTitle("Graphographic - " + filepath.Base(path))
+	StatusMsg = "Opened " + filepath.Base(path)
+}
+
+func saveGraph(path string) {
+	if err := Graph.SaveFile(path); err != nil {
+		rl.TraceLog(rl.LogWarning, "%s", err.Error())
+		StatusMsg = "Could not save " + filepath.Base(path)
+		return
+	}
+	CurrentFile = path
+	rl.SetWindowTitle("Graphographic - " + filepath.Base(path))
+	StatusMsg = "Saved " + filepath.Base(path)
+}
+
+func isControlDown() bool {
+	return rl.IsKeyDown(rl.KeyLeftControl) || rl.IsKeyDown(rl.KeyRightControl)
+}
+
+func isShiftDown() bool {
+	return rl.IsKeyDown(rl.KeyLeftShift) || rl.IsKeyDown(rl.KeyRightShift)
+}
+
 func spreadNodes() {
 	type pair struct {
 		n        *gr.Node
 		displace rl.Vector2
 	}
 	nodes := make([]pair, 0)
-	for nodeIt := Graph.Nodes.Front(); nodeIt != nil; nodeIt = nodeIt.Next() {
-		node := nodeIt.Value.(*gr.Node)
+	for node := range Graph.Nodes() {
 		node.Radius = calculateNodeRadius(node)
 		nodes = append(nodes, pair{
 			n:        node,
@@ -206,6 +319,76 @@ func spreadNodes() {
 	}
 }
 
+// the matrix panel and tour of the current algorithm as they are now, only as much
+// of the matrix as the panel shows
+func algorithmOverlay() tl.Overlay {
+	var overlay tl.Overlay
+	if matrix, ok := Algorithms[CurrentAlgorithm].(algo.Matrix); ok {
+		overlay.Labels = matrix.MatrixLabels()
+		n := min(len(overlay.Labels), MATRIX_PANEL_MAX_NODES)
+		for table := range matrix.MatrixCount() {
+			overlay.Titles = append(overlay.Titles, matrix.MatrixTitle(table))
+			cells := make([][]string, n)
+			for i := range n {
+				cells[i] = make([]string, n)
+				for j := range n {
+					cells[i][j] = matrix.MatrixCell(table, i, j)
+				}
+			}
+			overlay.Cells = append(overlay.Cells, cells)
+		}
+	}
+	if tour, ok := Algorithms[CurrentAlgorithm].(algo.Tour); ok {
+		nodes, closed := tour.Tour()
+		overlay.Tour, overlay.TourClosed = slices.Clone(nodes), closed
+	}
+	return overlay
+}
+
+// the overlay of the step the timeline shows, which may be an earlier one
+func shownOverlay() tl.Overlay {
+	if Timeline == nil {
+		return algorithmOverlay()
+	}
+	return Timeline.Overlay()
+}
+
+// show the next step of the run, replayed from the timeline when it was rewound or
+// made by updating the algorithm at its latest step
+func stepForward() {
+	if Timeline == nil || Timeline.Forward() || !IsAlgorithmRunning {
+		return
+	}
+	running, err := Algorithms[CurrentAlgorithm].Update()
+	Timeline.Record(&Graph, algorithmOverlay())
+	IsAlgorithmRunning = running
+	if err != nil {
+		rl.TraceLog(rl.LogWarning, "%s", err.Error())
+		AlgorithmErrorMsg = err.Error()
+	} else if !running {
+		AlgorithmResult = Algorithms[CurrentAlgorithm].Result()
+	}
+}
+
+// replace the graph with the one made from the outcome of the algorithm, can be undone
+func buildGraph(builder algo.Builder) {
+	g, err := builder.Build()
+	if err != nil {
+		rl.TraceLog(rl.LogWarning, "%s", err.Error())
+		AlgorithmErrorMsg = err.Error()
+		return
+	}
+	History.Do(&Graph, &hist.ReplaceGraph{G: g})
+	NodeA = nil
+	NodeB = nil
+	EdgeA = nil
+	EdgeAPair = nil
+	IsAlgorithmRunning = false
+	resetAlgoDataState()
+	Algorithms[CurrentAlgorithm].Init()
+	StatusMsg = "Built a new graph with " + Algorithms[CurrentAlgorithm].GetName()
+}
+
 // transforms mouse coordinates from screem space to world space
 func getMouseWorldPos() rl.Vector2 {
 	mousePos := MousePos
@@ -226,8 +409,7 @@ func getScreenPos(position rl.Vector2) rl.Vector2 {
 // mousePos must be in screen space, node positions will be transformed into screen space
 func findNodeUnderMouse() *gr.Node {
 	var ret *gr.Node = nil
-	for nodeIt := Graph.Nodes.Front(); nodeIt != nil; nodeIt = nodeIt.Next() {
-		node := nodeIt.Value.(*gr.Node)
+	for node := range Graph.Nodes() {
 		if isNodeUnderMouse(node) {
 			return node
 		}
@@ -242,8 +424,7 @@ func isNodeUnderMouse(node *gr.Node) bool {
 // mousePos must be in screen space, node positions will be transformed into screen space
 func findEdgeUnderMouse() *gr.Edge {
 	var ret *gr.Edge = nil
-	for edgeIt := Graph.Edges.Front(); edgeIt != nil; edgeIt = edgeIt.Next() {
-		edge := edgeIt.Value.(*gr.Edge)
+	for edge := range Graph.Edges() {
 		if isEdgeUnderMouse(edge) {
 			return edge
 		}
@@ -265,41 +446,15 @@ func isEdgeUnderMouse(edge *gr.Edge) bool {
 	return distFromLine(edge.StartPos, edge.EndPos, MousePos) < 10 && distFromCenter < rl.Vector2Length(halfWay)+10
 }
 
-func revertLatestAction() {
-	if len(ActionHistory) == 0 {
-		return
-	}
-	latest := ActionHistory[len(ActionHistory)-1]
-	ActionHistory = ActionHistory[0 : len(ActionHistory)-1]
-	if addN, ok := latest.(*hist.AddNode); ok {
-		Graph.RemoveNode(addN.N)
-	} else if addE, ok := latest.(*hist.AddEdge); ok {
-		Graph.RemoveEdge(addE.E)
-	} else if remN, ok := latest.(*hist.RemoveNode); ok {
-		Graph.Nodes.PushBack(remN.N)
-	} else if remE, ok := latest.(*hist.RemoveEdge); ok {
-		Graph.Edges.PushBack(remE.E)
-		remE.E.Head.Edges.PushBack(remE.E)
-		remE.E.Tail.Edges.PushBack(remE.E)
-	} else if chE, ok := latest.(*hist.EditEdgeCost); ok {
-		chE.E.Cost = chE.CostPreChange
-	} else if chN, ok := latest.(*hist.EditNodeContent); ok {
-		chN.N.Content = chN.ContentPreChange
-	} else if chN, ok := latest.(*hist.MoveNode); ok {
-		chN.N.Position = chN.PosPreChange
-	} else if _, ok := latest.(*hist.NodeSelected); ok {
-		Algorithms[CurrentAlgorithm].UndoSelect()
-	}
-
-}
-
 func update() {
 	MousePos = rl.GetMousePosition()
 	UpdateCounter++
 	mousePosWorld := getMouseWorldPos()
 
-	if IsAlgorithmRunning && UpdateCounter%uint64(AlgorithmSpeed) == 0 {
-		IsAlgorithmRunning = Algorithms[CurrentAlgorithm].Update()
+	// a run that was rewound plays on to its latest step even when it finished
+	playing := IsAlgorithmRunning || Timeline != nil && !Timeline.Latest()
+	if playing && !IsAlgorithmPaused && UpdateCounter%uint64(AlgorithmSpeed) == 0 {
+		stepForward()
 	}
 
 	if rl.IsWindowResized() {
@@ -326,7 +481,9 @@ func update() {
 		if rl.IsKeyReleased(rl.KeyP) {
 			Mode = MODE_PLACE
 		}
-		if rl.IsKeyReleased(rl.KeyC) {
+		if rl.IsKeyReleased(rl.KeyC) && isControlDown() {
+			copyResult()
+		} else if rl.IsKeyReleased(rl.KeyC) {
 			Mode = MODE_CONNECT
 		}
 		if rl.IsKeyReleased(rl.KeyD) && rl.IsKeyDown(rl.KeyLeftShift) {
@@ -334,14 +491,38 @@ func update() {
 		} else if rl.IsKeyReleased(rl.KeyD) {
 			Mode = MODE_DELETE
 		}
-		if rl.IsKeyReleased(rl.KeyBackspace) {
-			revertLatestAction()
+		if rl.IsKeyReleased(rl.KeyBackspace) || rl.IsKeyReleased(rl.KeyZ) && isControlDown() && !isShiftDown() {
+			History.Undo(&Graph)
+		} else if rl.IsKeyReleased(rl.KeyY) && isControlDown() || rl.IsKeyReleased(rl.KeyZ) && isControlDown() && isShiftDown() {
+			History.Redo(&Graph)
 		}
-		if rl.IsKeyReleased(rl.KeyS) {
+		if rl.IsKeyReleased(rl.KeyS) && isControlDown() {
+			if CurrentFile == "" {
+				saveGraph(DEFAULT_FILE_NAME)
+			} else {
+				saveGraph(CurrentFile)
+			}
+		} else if rl.IsKeyReleased(rl.KeyO) && isControlDown() {
+			if CurrentFile == "" {
+				openGraph(DEFAULT_FILE_NAME)
+			} else {
+				openGraph(CurrentFile)
+			}
+		} else if rl.IsKeyReleased(rl.KeyS) {
 			CurrentAlgorithm = wrap(CurrentAlgorithm+1, 0, len(Algorithms)-1)
 			resetAlgoDataState()
 			Algorithms[CurrentAlgorithm].Init()
-			CurrentAlgorithmName = Algorithms[CurrentAlgorithm].GetName()
+			CurrentAlgorithmName = algorithmName()
+		}
+		if conf, ok := Algorithms[CurrentAlgorithm].(algo.Configurable); ok && rl.IsKeyReleased(rl.KeyV) {
+			conf.NextOption()
+			CurrentAlgorithmName = algorithmName()
+		}
+		if matrix, ok := Algorithms[CurrentAlgorithm].(algo.Matrix); ok && rl.IsKeyReleased(rl.KeyTab) {
+			MatrixTable = wrap(MatrixTable+1, 0, matrix.MatrixCount()-1)
+		}
+		if builder, ok := Algorithms[CurrentAlgorithm].(algo.Builder); ok && rl.IsKeyReleased(rl.KeyK) && Mode == MODE_ALGORITHM {
+			buildGraph(builder)
 		}
 		if rl.IsKeyReleased(rl.KeyR) && Mode == MODE_ALGORITHM {
 			resetAlgoDataState()
@@ -351,12 +532,36 @@ func update() {
 				AlgorithmErrorMsg = err.Error()
 			} else {
 				IsAlgorithmRunning = true
+				Timeline = tl.New(&Graph, algorithmOverlay())
+			}
+		}
+		if Timeline != nil && Mode == MODE_ALGORITHM {
+			if rl.IsKeyReleased(rl.KeySpace) {
+				IsAlgorithmPaused = !IsAlgorithmPaused
+			}
+			if rl.IsKeyPressed(rl.KeyLeft) || rl.IsKeyPressedRepeat(rl.KeyLeft) {
+				IsAlgorithmPaused = true
+				Timeline.Back()
+			}
+			if rl.IsKeyPressed(rl.KeyRight) || rl.IsKeyPressedRepeat(rl.KeyRight) {
+				IsAlgorithmPaused = true
+				stepForward()
+			}
+			if rl.IsMouseButtonDown(rl.MouseLeftButton) && rl.CheckCollisionPointRec(MousePos, timelineBarRect()) {
+				bar := timelineBarRect()
+				IsAlgorithmPaused = true
+				Timeline.Seek(int(math.Round(float64((MousePos.X - bar.X) / bar.Width * float32(Timeline.Len())))))
 			}
 		}
 	}
 	if rl.IsKeyReleased(rl.KeyEscape) {
 		NodeA = nil
 	}
+	if rl.IsFileDropped() {
+		if files := rl.LoadDroppedFiles(); len(files) > 0 {
+			openGraph(files[0])
+		}
+	}
 
 	if rl.IsMouseButtonDown(rl.MouseButtonLeft) {
 		switch Mode {
@@ -372,14 +577,13 @@ func update() {
 				NodeB = &gr.Node{
 					Position: NodeA.Position,
 					Content:  "Node",
-					Edges:    list.New(),
 				}
 			}
 		case MODE_MOVE:
 			if NodeA == nil {
 				NodeA = findNodeUnderMouse()
 				if NodeA != nil {
-					ActionHistory = append(ActionHistory, &hist.MoveNode{N: NodeA, PosPreChange: NodeA.Position})
+					History.Record(&hist.MoveNode{ID: NodeA.ID, Pos: NodeA.Position})
 				}
 			}
 			if NodeA != nil {
@@ -394,64 +598,78 @@ func update() {
 			node := gr.NewNode()
 			node.Position = mousePosWorld
 			node.Content = "Node"
-			Graph.Nodes.PushBack(
-				&node,
-			)
-			ActionHistory = append(ActionHistory, &hist.AddNode{N: &node})
+			History.Do(&Graph, &hist.AddNode{N: node})
 		case MODE_CONNECT:
 			NodeB = findNodeUnderMouse()
-			if NodeA != nil && NodeB != nil && !NodeA.IsConnectedTo(NodeB) && NodeA != NodeB {
-				edge := Graph.AddEdge(NodeA, NodeB)
-				ActionHistory = append(ActionHistory, &hist.AddEdge{E: edge})
+			if NodeA != nil && NodeB != nil && Graph.EdgeBetween(NodeA, NodeB) == nil && NodeA != NodeB {
+				History.Begin()
+				forward := &hist.AddEdge{Tail: NodeA.ID, Head: NodeB.ID}
+				History.Do(&Graph, forward)
 				if !Directed {
-					edge := Graph.AddEdge(NodeB, NodeA)
-					ActionHistory = append(ActionHistory, &hist.AddEdge{E: edge})
+					History.Do(&Graph, &hist.AddEdge{Tail: NodeB.ID, Head: NodeA.ID, Pair: forward.ID})
 				}
+				History.End()
 			}
 			NodeA = nil
 			NodeB = nil
 		case MODE_APPEND:
 			if NodeA != nil && NodeB != nil {
-				Graph.Nodes.PushBack(NodeB)
-				ActionHistory = append(ActionHistory, &hist.AddNode{N: NodeB})
-				edge := Graph.AddEdge(NodeA, NodeB)
-				ActionHistory = append(ActionHistory, &hist.AddEdge{E: edge})
+				added := &hist.AddNode{N: *NodeB}
+				History.Begin()
+				History.Do(&Graph, added)
+				forward := &hist.AddEdge{Tail: NodeA.ID, Head: added.N.ID}
+				History.Do(&Graph, forward)
 				if !Directed {
-					edge := Graph.AddEdge(NodeB, NodeA)
-					ActionHistory = append(ActionHistory, &hist.AddEdge{E: edge})
+					History.Do(&Graph, &hist.AddEdge{Tail: added.N.ID, Head: NodeA.ID, Pair: forward.ID})
 				}
+				History.End()
 			}
 			NodeA = nil
 			NodeB = nil
 		case MODE_EDIT:
 			EdgeA = nil
+			EdgeAPair = nil
 			NodeA = nil
 			NodeA = findNodeUnderMouse()
 			if NodeA == nil {
 				if EdgeA = findEdgeUnderMouse(); EdgeA != nil {
-					ActionHistory = append(ActionHistory, &hist.EditEdgeCost{E: EdgeA, CostPreChange: EdgeA.Cost})
+					// both edges of an undirected pair get the new cost, a loop is its own pair
+					if pair := EdgeA.Pair(); pair != EdgeA {
+						EdgeAPair = pair
+					}
+					History.Begin()
+					History.Record(&hist.EditEdgeCost{ID: EdgeA.ID, Cost: EdgeA.Cost})
+					if EdgeAPair != nil {
+						History.Record(&hist.EditEdgeCost{ID: EdgeAPair.ID, Cost: EdgeAPair.Cost})
+					}
+					History.End()
 					SelectedEdgeScratch = fmt.Sprintf("%d", EdgeA.Cost)
 				}
 			} else {
-				ActionHistory = append(ActionHistory, &hist.EditNodeContent{N: NodeA, ContentPreChange: NodeA.Content})
+				History.Record(&hist.EditNodeContent{ID: NodeA.ID, Content: NodeA.Content})
 			}
 		case MODE_MOVE:
 			NodeA = nil
 		case MODE_ALGORITHM:
-			slc := findNodeUnderMouse()
-			Algorithms[CurrentAlgorithm].NodeSelected(slc)
-			if slc != nil {
-				ActionHistory = append(ActionHistory, &hist.NodeSelected{N: slc})
+			// a click on the timeline scrubs it, nothing is selected
+			if Timeline != nil && rl.CheckCollisionPointRec(MousePos, timelineBarRect()) {
+				break
+			}
+			if slc := findNodeUnderMouse(); slc != nil {
+				History.Do(&Graph, &hist.NodeSelected{ID: slc.ID, Algo: Algorithms[CurrentAlgorithm]})
+			} else {
+				Algorithms[CurrentAlgorithm].NodeSelected(nil)
 			}
 		case MODE_DELETE:
+			// a node and an edge removed by the same click are undone together
+			History.Begin()
 			if toDelete := findNodeUnderMouse(); toDelete != nil {
-				Graph.RemoveNode(toDelete)
-				ActionHistory = append(ActionHistory, &hist.RemoveNode{N: toDelete})
+				History.Do(&Graph, &hist.RemoveNode{ID: toDelete.ID})
 			}
 			if toDelete := findEdgeUnderMouse(); toDelete != nil {
-				Graph.RemoveEdge(toDelete)
-				ActionHistory = append(ActionHistory, &hist.RemoveEdge{E: toDelete})
+				History.Do(&Graph, &hist.RemoveEdge{ID: toDelete.ID})
 			}
+			History.End()
 		}
 	}
 
@@ -468,8 +686,13 @@ func update() {
 	if Mode == MODE_APPEND && NodeB != nil {
 		NodeB.Position = mousePosWorld
 	}
-	if len(ActionHistory) > ACTION_HISTORY_MAX_SIZE {
-		_, ActionHistory = ActionHistory[0], ActionHistory[1:]
+}
+
+// EdgeAPair is the other edge of the undirected pair EdgeA is in, it gets the same cost
+func setSelectedEdgeCost(cost int32) {
+	EdgeA.Cost = cost
+	if EdgeAPair != nil {
+		EdgeAPair.Cost = cost
 	}
 }
 
@@ -486,7 +709,7 @@ func editModeTyping() {
 				SelectedEdgeScratch = SelectedEdgeScratch[0:end]
 			}
 			if d, err := strconv.Atoi(SelectedEdgeScratch); err == nil {
-				selected.Cost = int32(d)
+				setSelectedEdgeCost(int32(d))
 			} else {
 				SelectedEdgeScratch = fmt.Sprintf("%d", selected.Cost)
 			}
@@ -506,7 +729,7 @@ func editModeTyping() {
 			}
 
 			if d, err := strconv.Atoi(SelectedEdgeScratch); err == nil {
-				selected.Cost = int32(d)
+				setSelectedEdgeCost(int32(d))
 			} else {
 				SelectedEdgeScratch = fmt.Sprintf("%d", selected.Cost)
 			}
@@ -523,6 +746,7 @@ func draw() {
 		}
 	}
 	drawGraph()
+	drawMatrixPanel(shownOverlay())
 	var mode string = "Mode: "
 	var directed string
 	if Directed {
@@ -565,6 +789,16 @@ func draw() {
 		FONT_SPACING,
 		rl.Red,
 	)
+	if StatusMsg != "" {
+		rl.DrawTextEx(
+			rl.GetFontDefault(),
+			StatusMsg,
+			rl.Vector2{X: 0, Y: size.Y},
+			FONT_SIZE-6,
+			FONT_SPACING,
+			rl.Red,
+		)
+	}
 	algoName := "Algorithm: " + CurrentAlgorithmName
 	size = rl.MeasureTextEx(rl.GetFontDefault(), algoName, FONT_SIZE-4, FONT_SPACING)
 	rl.DrawTextEx(
@@ -575,7 +809,15 @@ func draw() {
 		FONT_SPACING,
 		rl.Red,
 	)
-	if AlgorithmErrorMsg != "" {
+	// the result and errors belong to the latest step
+	latest := Timeline == nil || Timeline.Latest()
+	if AlgorithmResult != nil && Mode == MODE_ALGORITHM && latest {
+		drawResultPanel(AlgorithmResult)
+	}
+	if Timeline != nil && Mode == MODE_ALGORITHM {
+		drawTimeline()
+	}
+	if AlgorithmErrorMsg != "" && latest {
 		if !IsAlgorithmRunning {
 			algoErr := "Error: " + AlgorithmErrorMsg
 			size = rl.MeasureTextEx(rl.GetFontDefault(), algoErr, FONT_SIZE-6, FONT_SPACING)
@@ -594,18 +836,151 @@ func draw() {
 	}
 }
 
+// the scrub bar of the timeline at the bottom of the window, in screen space
+func timelineBarRect() rl.Rectangle {
+	return rl.Rectangle{X: float32(Width) / 3, Y: float32(Height) - 30, Width: float32(Width) / 3, Height: 12}
+}
+
+// the scrub bar with a marker on the shown step
+func drawTimeline() {
+	bar := timelineBarRect()
+	rl.DrawRectangleRec(bar, rl.Fade(GraphColor, 0.2))
+	rl.DrawRectangleLinesEx(bar, 1, GraphColor)
+	if Timeline.Len() > 0 {
+		x := bar.X + bar.Width*float32(Timeline.Pos())/float32(Timeline.Len())
+		rl.DrawRectangleRec(rl.Rectangle{X: x - 3, Y: bar.Y - 4, Width: 6, Height: bar.Height + 8}, SelectedNodeColor)
+	}
+	text := fmt.Sprintf("Step %d/%d", Timeline.Pos(), Timeline.Len())
+	if IsAlgorithmPaused {
+		text += " (paused)"
+	}
+	text += "  SPACE pause, LEFT/RIGHT step"
+	const fontSize = FONT_SIZE - 10
+	size := rl.MeasureTextEx(rl.GetFontDefault(), text, fontSize, 1)
+	rl.DrawTextEx(rl.GetFontDefault(), text, rl.Vector2{X: bar.X + (bar.Width-size.X)/2, Y: bar.Y - size.Y - 6}, fontSize, 1, rl.Red)
+}
+
+// puts the result of the last run on the clipboard
+func copyResult() {
+	if AlgorithmResult == nil || Mode != MODE_ALGORITHM {
+		return
+	}
+	rl.SetClipboardText(CurrentAlgorithmName + "\n" + AlgorithmResult.String())
+	StatusMsg = "Copied the result to the clipboard"
+}
+
+// draws the result of the last run in the bottom left corner
+func drawResultPanel(result *algo.Result) {
+	lines := []string{result.Summary}
+	for _, m := range result.Metrics {
+		lines = append(lines, m.String())
+	}
+	if len(result.Nodes) > 0 {
+		lines = append(lines, "Nodes: "+result.NodeList())
+	}
+	if len(result.Edges) > 0 {
+		lines = append(lines, "Edges: "+result.EdgeList())
+	}
+	lines = append(lines, "CTRL+C to copy")
+	const fontSize = FONT_SIZE - 10
+	const padding = 6
+	width := float32(0)
+	for i, line := range lines {
+		if runes := []rune(line); len(runes) > RESULT_PANEL_MAX_CHARS {
+			lines[i] = string(runes[:RESULT_PANEL_MAX_CHARS-3]) + "..."
+		}
+		width = max(width, rl.MeasureTextEx(rl.GetFontDefault(), lines[i], fontSize, 1).X)
+	}
+	width += 2 * padding
+	lineHeight := float32(fontSize + padding)
+	height := lineHeight*float32(len(lines)) + padding
+	// above the mode line
+	top := float32(Height) - height - rl.MeasureTextEx(rl.GetFontDefault(), "Mode", FONT_SIZE, FONT_SPACING).Y
+	rl.DrawRectangleRec(rl.Rectangle{X: 0, Y: top, Width: width, Height: height}, rl.Fade(BackgroundColor, 0.9))
+	rl.DrawRectangleLinesEx(rl.Rectangle{X: 0, Y: top, Width: width, Height: height}, 1, GraphColor)
+	for i, line := range lines {
+		color := rl.DarkBlue
+		if i == 0 {
+			color = rl.Red
+		}
+		rl.DrawTextEx(rl.GetFontDefault(), line, rl.Vector2{X: padding, Y: top + padding + lineHeight*float32(i)}, fontSize, 1, color)
+	}
+}
+
+// draws the table of a Matrix algorithm along the right edge of the window
+func drawMatrixPanel(overlay tl.Overlay) {
+	labels := overlay.Labels
+	if len(labels) == 0 || len(overlay.Titles) == 0 {
+		return
+	}
+	table := clamp(MatrixTable, 0, len(overlay.Titles)-1)
+	title := overlay.Titles[table] + " (TAB to switch)"
+	n := len(overlay.Cells[table])
+	if n < len(labels) {
+		title += fmt.Sprintf(", first %d of %d nodes", n, len(labels))
+	}
+	const fontSize = FONT_SIZE - 10
+	const padding = 6
+	cellWidth := float32(0)
+	for i := range n {
+		cellWidth = max(cellWidth, rl.MeasureTextEx(rl.GetFontDefault(), labels[i], fontSize, 1).X)
+		for j := range n {
+			cellWidth = max(cellWidth, rl.MeasureTextEx(rl.GetFontDefault(), overlay.Cells[table][i][j], fontSize, 1).X)
+		}
+	}
+	cellWidth += padding
+	cellHeight := float32(fontSize + padding)
+	width := max(cellWidth*float32(n+1), rl.MeasureTextEx(rl.GetFontDefault(), title, fontSize, 1).X) + 2*padding
+	height := cellHeight*float32(n+2) + 2*padding
+	left := float32(Width) - width
+	top := float32(FONT_SIZE + padding)
+	rl.DrawRectangleRec(rl.Rectangle{X: left, Y: top, Width: width, Height: height}, rl.Fade(BackgroundColor, 0.9))
+	rl.DrawRectangleLinesEx(rl.Rectangle{X: left, Y: top, Width: width, Height: height}, 1, GraphColor)
+	left += padding
+	top += padding
+	rl.DrawTextEx(rl.GetFontDefault(), title, rl.Vector2{X: left, Y: top}, fontSize, 1, rl.Red)
+	top += cellHeight
+	for i := range n {
+		rl.DrawTextEx(rl.GetFontDefault(), labels[i], rl.Vector2{X: left + cellWidth*float32(i+1), Y: top}, fontSize, 1, rl.DarkBlue)
+		rl.DrawTextEx(rl.GetFontDefault(), labels[i], rl.Vector2{X: left, Y: top + cellHeight*float32(i+1)}, fontSize, 1, rl.DarkBlue)
+		for j := range n {
+			rl.DrawTextEx(
+				rl.GetFontDefault(),
+				overlay.Cells[table][i][j],
+				rl.Vector2{X: left + cellWidth*float32(j+1), Y: top + cellHeight*float32(i+1)},
+				fontSize,
+				1,
+				GraphColor,
+			)
+		}
+	}
+}
+
 func drawGraph() {
 	// draw edges
-	for edgeIt := Graph.Edges.Front(); edgeIt != nil; edgeIt = edgeIt.Next() {
-		edge := edgeIt.Value.(*gr.Edge)
+	for edge := range Graph.Edges() {
 		drawEdge(edge)
 	}
+	if Mode == MODE_ALGORITHM {
+		drawTour(shownOverlay())
+	}
 	// draw nodes
-	for nodeIt := Graph.Nodes.Front(); nodeIt != nil; nodeIt = nodeIt.Next() {
-		node := nodeIt.Value.(*gr.Node)
+	for node := range Graph.Nodes() {
 		drawNode(node)
 	}
 }
+
+// lines between the nodes of the tour, they do not have to be connected by edges
+func drawTour(overlay tl.Overlay) {
+	nodes, closed := overlay.Tour, overlay.TourClosed
+	for i := 1; i < len(nodes); i++ {
+		rl.DrawLineEx(getScreenPos(nodes[i-1].Position), getScreenPos(nodes[i].Position), 4*Scale, rl.Orange)
+	}
+	if closed && len(nodes) > 1 {
+		rl.DrawLineEx(getScreenPos(nodes[len(nodes)-1].Position), getScreenPos(nodes[0].Position), 4*Scale, rl.Orange)
+	}
+}
+
 func drawEdge(edge *gr.Edge) {
 	tailPos := edge.Tail.Position
 	headPos := edge.Head.Position
@@ -632,6 +1007,8 @@ func drawEdge(edge *gr.Edge) {
 
 	if edge.Data.Highlighted && Mode == MODE_ALGORITHM {
 		color = SelectedNodeColor
+	} else if edge.Data.Group != 0 && Mode == MODE_ALGORITHM {
+		color = groupColor(edge.Data.Group)
 	} else if edge.Data.Explored && Mode == MODE_ALGORITHM {
 		color = rl.Green
 	} else if edge == EdgeA && Mode == MODE_EDIT {
@@ -648,6 +1025,10 @@ func drawEdge(edge *gr.Edge) {
 
 	textPos := rl.Vector2Add(tailPos, halfWay)
 	costText := fmt.Sprintf("Cost: %d", edge.Cost)
+	// algorithms can put something more telling there, like the flow through the edge
+	if edge.Data.Tag != "" && Mode == MODE_ALGORITHM {
+		costText = edge.Data.Tag
+	}
 	size := rl.MeasureTextEx(rl.GetFontDefault(), costText, (FONT_SIZE-8)*Scale, FONT_SPACING)
 	textPos = rl.Vector2Add(textPos, rl.Vector2Scale(rl.Vector2Rotate(rl.Vector2Normalize(halfWay), 90*math.Pi/180), -20))
 
@@ -674,6 +1055,8 @@ func drawNode(node *gr.Node) {
 	amISelected := (Mode == MODE_EDIT || Mode == MODE_MOVE) && node == NodeA
 	if node.Data.Highlighted && Mode == MODE_ALGORITHM {
 		color = SelectedNodeColor
+	} else if node.Data.Group != 0 && Mode == MODE_ALGORITHM {
+		color = groupColor(node.Data.Group)
 	} else if node.Data.Explored && Mode == MODE_ALGORITHM {
 		color = rl.Green
 	} else if amISelected {
@@ -726,6 +1109,14 @@ func drawNode(node *gr.Node) {
 		)
 	}
 }
+
+// colour of a group assigned by an algorithm, hues a golden angle apart so
+// neighbouring groups never look alike
+func groupColor(group int) rl.Color {
+	hue := math.Mod(float64(group)*137.508, 360)
+	return rl.ColorFromHSV(float32(hue), 0.75, 0.8)
+}
+
 func calculateNodeRadius(node *gr.Node) float32 {
 	radius := rl.MeasureTextEx(rl.GetFontDefault(), node.Content, float32(FONT_SIZE*Scale), FONT_SPACING).X * 0.5 * Scale
 	radius = float32(math.Max(float64(radius), MIN_RADIUS))
@@ -0,0 +1,167 @@
+package timeline
+
+import (
+	gr "graphographic/graph"
+)
+
+// The part of AlgoData that is drawn. Custom belongs to the running algorithm and
+// is left alone, going back shows an earlier step without changing the algorithm
+type view struct {
+	explored    bool
+	highlighted bool
+	tag         string
+	group       int
+}
+
+func viewOf(d *gr.AlgoData) view {
+	return view{d.Explored, d.Highlighted, d.Tag, d.Group}
+}
+
+func (v view) set(d *gr.AlgoData) {
+	d.Explored, d.Highlighted, d.Tag, d.Group = v.explored, v.highlighted, v.tag, v.group
+}
+
+type nodeChange struct {
+	node          *gr.Node
+	before, after view
+}
+
+type edgeChange struct {
+	edge          *gr.Edge
+	before, after view
+}
+
+// What an algorithm shows next to the graph, kept whole for every step since
+// it is read from the algorithm that only knows its latest state
+type Overlay struct {
+	// tables of a Matrix algorithm, the cells by table, row and column
+	Titles []string
+	Labels []string
+	Cells  [][][]string
+	// nodes of a Tour algorithm
+	Tour       []*gr.Node
+	TourClosed bool
+}
+
+// What one update of an algorithm changed
+type step struct {
+	nodes   []nodeChange
+	edges   []edgeChange
+	overlay Overlay
+}
+
+// Steps of an algorithm run that can be gone through backwards and forwards again.
+// Only the latest step is where the algorithm really is, it can only go on from there
+type Timeline struct {
+	steps []step
+	// how many steps are shown, len(steps) when at the latest one
+	pos int
+	// state after the latest step, the next step is what differs from it
+	nodes map[*gr.Node]view
+	edges map[*gr.Edge]view
+	// overlay before the first update
+	start Overlay
+}
+
+// Start a timeline from the state of the graph and overlay before the first update
+func New(g *gr.Graph, overlay Overlay) *Timeline {
+	t := &Timeline{
+		steps: make([]step, 0),
+		nodes: make(map[*gr.Node]view, g.NodeCount()),
+		edges: make(map[*gr.Edge]view, g.EdgeCount()),
+		start: overlay,
+	}
+	for n := range g.Nodes() {
+		t.nodes[n] = viewOf(&n.Data)
+	}
+	for e := range g.Edges() {
+		t.edges[e] = viewOf(&e.Data)
+	}
+	return t
+}
+
+// Add what the latest update changed as a new step, the graph has to be at the
+// latest step when the algorithm is updated
+func (t *Timeline) Record(g *gr.Graph, overlay Overlay) {
+	s := step{overlay: overlay}
+	for n := range g.Nodes() {
+		if now := viewOf(&n.Data); now != t.nodes[n] {
+			s.nodes = append(s.nodes, nodeChange{n, t.nodes[n], now})
+			t.nodes[n] = now
+		}
+	}
+	for e := range g.Edges() {
+		if now := viewOf(&e.Data); now != t.edges[e] {
+			s.edges = append(s.edges, edgeChange{e, t.edges[e], now})
+			t.edges[e] = now
+		}
+	}
+	t.steps = append(t.steps, s)
+	t.pos = len(t.steps)
+}
+
+// Undo the shown step, false at the start
+func (t *Timeline) Back() bool {
+	if t.pos == 0 {
+		return false
+	}
+	t.pos--
+	s := t.steps[t.pos]
+	for _, c := range s.nodes {
+		c.before.set(&c.node.Data)
+	}
+	for _, c := range s.edges {
+		c.before.set(&c.edge.Data)
+	}
+	return true
+}
+
+// Show the next recorded step again, false at the latest one
+func (t *Timeline) Forward() bool {
+	if t.pos == len(t.steps) {
+		return false
+	}
+	s := t.steps[t.pos]
+	t.pos++
+	for _, c := range s.nodes {
+		c.after.set(&c.node.Data)
+	}
+	for _, c := range s.edges {
+		c.after.set(&c.edge.Data)
+	}
+	return true
+}
+
+// Go back or forward to the step at pos, 0 is the state before the first update
+func (t *Timeline) Seek(pos int) {
+	pos = max(0, min(pos, len(t.steps)))
+	for t.pos > pos {
+		t.Back()
+	}
+	for t.pos < pos {
+		t.Forward()
+	}
+}
+
+// Overlay of the shown step
+func (t *Timeline) Overlay() Overlay {
+	if t.pos == 0 {
+		return t.start
+	}
+	return t.steps[t.pos-1].overlay
+}
+
+// Number of the shown step
+func (t *Timeline) Pos() int {
+	return t.pos
+}
+
+// Number of recorded steps
+func (t *Timeline) Len() int {
+	return len(t.steps)
+}
+
+// True when the latest step is shown
+func (t *Timeline) Latest() bool {
+	return t.pos == len(t.steps)
+}
@@ -0,0 +1,70 @@
+package timeline
+
+import (
+	gr "graphographic/graph"
+	"slices"
+	"testing"
+)
+
+type state struct {
+	tag      string
+	explored bool
+}
+
+func snapshot(g *gr.Graph) []state {
+	states := make([]state, 0)
+	for n := range g.Nodes() {
+		states = append(states, state{n.Data.Tag, n.Data.Explored})
+	}
+	for e := range g.Edges() {
+		states = append(states, state{e.Data.Tag, e.Data.Explored})
+	}
+	return states
+}
+
+func TestBackAndForward(t *testing.T) {
+	g := gr.New()
+	a := g.AddNode(gr.NewNode())
+	b := g.AddNode(gr.NewNode())
+	e := g.AddEdge(a, b)
+	a.Data.Custom = "algorithm state"
+
+	tl := New(&g, Overlay{})
+	states := [][]state{snapshot(&g)}
+	// three updates of a made up algorithm, the tour grows with each
+	a.Data.Explored, a.Data.Tag = true, "0"
+	tl.Record(&g, Overlay{Tour: []*gr.Node{a}})
+	states = append(states, snapshot(&g))
+	e.Data.Explored, b.Data.Tag = true, "1"
+	tl.Record(&g, Overlay{Tour: []*gr.Node{a, b}})
+	states = append(states, snapshot(&g))
+	tl.Record(&g, Overlay{Tour: []*gr.Node{a, b}, TourClosed: true})
+	states = append(states, snapshot(&g))
+
+	if tl.Len() != 3 || !tl.Latest() {
+		t.Fatalf("%d steps, latest: %v", tl.Len(), tl.Latest())
+	}
+	for pos := 2; pos >= 0; pos-- {
+		if !tl.Back() || !slices.Equal(snapshot(&g), states[pos]) {
+			t.Fatalf("going back to step %d shows %v, want %v", pos, snapshot(&g), states[pos])
+		}
+		if len(tl.Overlay().Tour) != min(pos, 2) {
+			t.Fatalf("step %d shows a tour of %d nodes", pos, len(tl.Overlay().Tour))
+		}
+	}
+	if tl.Back() {
+		t.Fatal("went back past the start")
+	}
+	tl.Seek(2)
+	if tl.Pos() != 2 || !slices.Equal(snapshot(&g), states[2]) {
+		t.Fatalf("seeking to step 2 shows %v", snapshot(&g))
+	}
+	for tl.Forward() {
+	}
+	if !tl.Latest() || !slices.Equal(snapshot(&g), states[3]) || !tl.Overlay().TourClosed {
+		t.Fatal("forward did not end at the latest step")
+	}
+	if a.Data.Custom != "algorithm state" {
+		t.Fatal("the data of the algorithm was touched")
+	}
+}